@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/samijaber1/aegis-slo/internal/slo"
 )
@@ -13,6 +14,8 @@ import (
 func main() {
 	validateCmd := flag.NewFlagSet("validate", flag.ExitOnError)
 	validateDir := validateCmd.String("dir", "", "directory containing SLO YAML files")
+	var validateSources stringList
+	validateCmd.Var(&validateSources, "source", "additional SLO source (file, glob:<pattern>, http(s) URL or git:<worktree>@<ref>[:<path>]); repeatable")
 
 	if len(os.Args) < 2 {
 		printUsage()
@@ -22,12 +25,12 @@ func main() {
 	switch os.Args[1] {
 	case "validate":
 		validateCmd.Parse(os.Args[2:])
-		if *validateDir == "" {
-			fmt.Fprintln(os.Stderr, "Error: --dir flag is required")
+		if *validateDir == "" && len(validateSources) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: --dir or --source flag is required")
 			validateCmd.Usage()
 			os.Exit(1)
 		}
-		os.Exit(runValidate(*validateDir))
+		os.Exit(runValidate(*validateDir, validateSources))
 	default:
 		printUsage()
 		os.Exit(1)
@@ -39,10 +42,11 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  validate --dir <path>    Validate SLO YAML files in a directory")
+	fmt.Println("  validate --source <src>  Validate SLOs from one or more sources (repeatable)")
 	fmt.Println()
 }
 
-func runValidate(dirPath string) int {
+func runValidate(dirPath string, sourceSpecs []string) int {
 	// Find schema file relative to the binary or in the current directory
 	schemaPath := findSchemaFile()
 	if schemaPath == "" {
@@ -57,8 +61,22 @@ func runValidate(dirPath string) int {
 		return 1
 	}
 
-	// Validate directory
-	errors := validator.ValidateDirectory(dirPath)
+	// Load every source, then validate them together so ID collisions are caught
+	var sources []slo.Source
+	if dirPath != "" {
+		sources = append(sources, &slo.DirectorySource{Path: dirPath})
+	}
+	for _, spec := range sourceSpecs {
+		source, err := slo.ParseSource(spec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		sources = append(sources, source)
+	}
+
+	sloWithFiles, errors := slo.LoadSources(sources)
+	errors = append(errors, validator.ValidateSLOs(sloWithFiles)...)
 
 	if len(errors) == 0 {
 		fmt.Println("✓ All SLO files are valid")
@@ -110,3 +128,15 @@ func findSchemaFile() string {
 
 	return ""
 }
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
@@ -7,7 +7,9 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/samijaber1/aegis-slo/internal/adapter/prometheus"
 	"github.com/samijaber1/aegis-slo/internal/adapter/synthetic"
@@ -16,6 +18,7 @@ import (
 	"github.com/samijaber1/aegis-slo/internal/eval"
 	"github.com/samijaber1/aegis-slo/internal/policy"
 	"github.com/samijaber1/aegis-slo/internal/scheduler"
+	"github.com/samijaber1/aegis-slo/internal/slo"
 	"github.com/samijaber1/aegis-slo/internal/storage/sqlite"
 )
 
@@ -29,13 +32,15 @@ func main() {
 	}
 
 	log.Printf("Starting AegisSLO server...")
-	log.Printf("Config: port=%d, slo-dir=%s, adapter=%s", cfg.Port, cfg.SLODirectory, cfg.AdapterType)
+	log.Printf("Config: port=%d, slo-dir=%s, slo-sources=%v, adapter=%s", cfg.Port, cfg.SLODirectory, cfg.SLOSources, cfg.AdapterType)
 
 	// Create metrics adapter
 	var metricsAdapter eval.MetricsAdapter
 	switch cfg.AdapterType {
 	case "prometheus":
 		promConfig := prometheus.DefaultConfig(cfg.PrometheusURL)
+		promConfig.CacheFreshness = cfg.QueryCacheFreshness
+		promConfig.SampleCountStep = cfg.SampleCountStep
 		metricsAdapter = prometheus.NewAdapter(promConfig)
 		log.Printf("Using Prometheus adapter: %s", cfg.PrometheusURL)
 
@@ -55,10 +60,40 @@ func main() {
 
 	// Create evaluator and policy engine
 	evaluator := eval.NewEvaluator(metricsAdapter)
+	if err := evaluator.SetStrategy(eval.Strategy(cfg.QueryStrategy), cfg.RangeStep); err != nil {
+		log.Fatalf("Invalid query strategy: %v", err)
+	}
+	log.Printf("Query strategy: %s", cfg.QueryStrategy)
 	policyEngine := policy.NewEngine()
+	if err := policyEngine.SetQueryErrorDecision(policy.Decision(cfg.QueryErrorDecision)); err != nil {
+		log.Fatalf("Invalid query error decision: %v", err)
+	}
+	if cfg.FreezeCalendarPath != "" {
+		calendar, err := policy.LoadFreezeCalendar(cfg.FreezeCalendarPath)
+		if err != nil {
+			log.Fatalf("Invalid freeze calendar: %v", err)
+		}
+		policyEngine.SetFreezeCalendar(calendar)
+		log.Printf("Loaded %d change freezes from %s", len(calendar.Freezes), cfg.FreezeCalendarPath)
+	}
 
 	// Create scheduler
 	sched := scheduler.NewScheduler(evaluator, policyEngine, cfg.SLODirectory)
+	sched.SetDiscoveryInterval(cfg.DiscoveryInterval)
+
+	// Combine the SLO directory with any additional sources
+	var sources []slo.Source
+	if cfg.SLODirectory != "" {
+		sources = append(sources, &slo.DirectorySource{Path: cfg.SLODirectory})
+	}
+	for _, spec := range cfg.SLOSources {
+		source, err := slo.ParseSource(spec)
+		if err != nil {
+			log.Fatalf("Invalid SLO source: %v", err)
+		}
+		sources = append(sources, source)
+	}
+	sched.SetSources(sources...)
 
 	// Initialize audit storage if database path is configured
 	var auditStorage *sqlite.Store
@@ -79,11 +114,50 @@ func main() {
 		log.Fatalf("Failed to load SLOs: %v", err)
 	}
 
+	if cfg.BackfillSLO != "" {
+		err := runBackfill(sched, cfg)
+		if closeErr := auditStorage.Close(); closeErr != nil {
+			log.Printf("Error closing database: %v", closeErr)
+		}
+		if err != nil {
+			log.Fatalf("Backfill failed: %v", err)
+		}
+		return
+	}
+
 	// Start scheduler
 	if err := sched.Start(); err != nil {
 		log.Fatalf("Failed to start scheduler: %v", err)
 	}
 
+	// Reload SLO definitions when the sources change or on SIGHUP
+	reloadCtx, stopReloads := context.WithCancel(context.Background())
+	defer stopReloads()
+
+	if cfg.WatchInterval > 0 {
+		go sched.Watch(reloadCtx, cfg.WatchInterval)
+		log.Printf("Watching SLO sources every %s", cfg.WatchInterval)
+	}
+
+	// Record the expiry of decision overrides
+	if auditStorage != nil {
+		go sched.WatchOverrides(reloadCtx, scheduler.DefaultOverrideExpiryInterval)
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-reloadCtx.Done():
+				return
+			case <-hangup:
+				log.Println("Received SIGHUP, reloading SLOs")
+				_, _ = sched.Reload()
+			}
+		}
+	}()
+
 	// Create and start HTTP server
 	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
 	apiServer := api.NewServer(sched, addr)
@@ -115,6 +189,7 @@ func main() {
 		}
 
 		log.Println("Stopping scheduler...")
+		stopReloads()
 		sched.Stop()
 
 		// Close audit storage
@@ -135,12 +210,77 @@ func parseFlags() config.Config {
 	flag.IntVar(&cfg.Port, "port", cfg.Port, "HTTP server port")
 	flag.StringVar(&cfg.Host, "host", cfg.Host, "HTTP server host")
 	flag.StringVar(&cfg.SLODirectory, "slo-dir", cfg.SLODirectory, "Directory containing SLO YAML files")
+	flag.Var((*stringList)(&cfg.SLOSources), "slo-source", "Additional SLO source (dir, file, glob:<pattern>, http(s) URL or git:<worktree>@<ref>[:<path>]); repeatable")
 	flag.StringVar(&cfg.AdapterType, "adapter", cfg.AdapterType, "Metrics adapter type (prometheus|synthetic)")
 	flag.StringVar(&cfg.PrometheusURL, "prometheus-url", cfg.PrometheusURL, "Prometheus server URL (required for prometheus adapter)")
 	flag.StringVar(&cfg.SyntheticFixDir, "synthetic-fixtures", cfg.SyntheticFixDir, "Directory containing synthetic metric fixtures")
+	flag.DurationVar(&cfg.SampleCountStep, "sample-count-step", cfg.SampleCountStep, "Resolution of the Prometheus query counting samples behind each window's total series for gating.minDataPoints (0 disables)")
+	flag.DurationVar(&cfg.QueryCacheFreshness, "query-cache-freshness", cfg.QueryCacheFreshness, "How long Prometheus query results are shared across SLOs (0 only deduplicates in-flight queries)")
+	flag.StringVar(&cfg.QueryStrategy, "query-strategy", cfg.QueryStrategy, "How windows are queried: instant (one query per window) or range (one range query per series, windows derived locally)")
+	flag.DurationVar(&cfg.RangeStep, "range-step", cfg.RangeStep, "Step of the range query used by the range query strategy")
+	flag.StringVar(&cfg.QueryErrorDecision, "on-query-error", cfg.QueryErrorDecision, "Decision when an evaluation's queries fail (ALLOW|WARN|BLOCK)")
+	flag.StringVar(&cfg.FreezeCalendarPath, "freeze-calendar", cfg.FreezeCalendarPath, "YAML file of change freezes that gate every decision (see README)")
+	flag.DurationVar(&cfg.DiscoveryInterval, "discovery-interval", cfg.DiscoveryInterval, "Interval between label value discoveries for expandBy SLOs")
+	flag.DurationVar(&cfg.WatchInterval, "watch-interval", cfg.WatchInterval, "Interval between polls of the SLO sources for changes (0 disables)")
 	flag.StringVar(&cfg.DatabasePath, "db", cfg.DatabasePath, "SQLite database file path for audit logging")
+	flag.StringVar(&cfg.BackfillSLO, "backfill", cfg.BackfillSLO, "Backfill mode: evaluate this SLO over a past time range, store the results as backfilled audit records and exit")
+	flag.Var((*timeFlag)(&cfg.BackfillStart), "backfill-start", "Start of the backfill range (RFC3339)")
+	flag.Var((*timeFlag)(&cfg.BackfillEnd), "backfill-end", "End of the backfill range (RFC3339, default now)")
+	flag.DurationVar(&cfg.BackfillStep, "backfill-step", cfg.BackfillStep, "Interval between backfilled evaluations")
 
 	flag.Parse()
 
 	return cfg
 }
+
+// runBackfill evaluates the configured SLO over the backfill range and logs a summary
+func runBackfill(sched *scheduler.Scheduler, cfg config.Config) error {
+	end := cfg.BackfillEnd
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Backfilling SLO %s from %s to %s every %s", cfg.BackfillSLO,
+		cfg.BackfillStart.Format(time.RFC3339), end.Format(time.RFC3339), cfg.BackfillStep)
+
+	result, err := sched.Backfill(ctx, cfg.BackfillSLO, cfg.BackfillStart, end, cfg.BackfillStep)
+	if result != nil {
+		log.Printf("Backfilled %d evaluations of SLO %s: ALLOW=%d WARN=%d BLOCK=%d", result.Evaluations, result.SLOID,
+			result.Decisions[policy.DecisionALLOW], result.Decisions[policy.DecisionWARN], result.Decisions[policy.DecisionBLOCK])
+	}
+	return err
+}
+
+// timeFlag is an RFC3339 time flag
+type timeFlag time.Time
+
+func (t *timeFlag) String() string {
+	if time.Time(*t).IsZero() {
+		return ""
+	}
+	return time.Time(*t).Format(time.RFC3339)
+}
+
+func (t *timeFlag) Set(value string) error {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return err
+	}
+	*t = timeFlag(parsed)
+	return nil
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
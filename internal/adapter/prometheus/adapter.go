@@ -7,13 +7,20 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/samijaber1/aegis-slo/internal/eval"
+	"github.com/samijaber1/aegis-slo/internal/slo"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
 )
 
+// maxPointsPerQuery keeps range queries below Prometheus' 11,000 points-per-series limit
+const maxPointsPerQuery = 10000
+
 // Config holds Prometheus adapter configuration
 type Config struct {
 	URL            string
@@ -21,8 +28,16 @@ type Config struct {
 	MaxConcurrency int64
 	RetryCount     int
 	RetryDelay     time.Duration
+	CacheFreshness time.Duration // how long instant query results are reused; 0 only deduplicates in-flight queries
+	// SampleCountStep, when positive, adds a count_over_time subquery at this resolution
+	// reporting how many steps of each window's total series have data
+	SampleCountStep time.Duration
 }
 
+// maxSampleCountSteps bounds the steps of a sample count subquery; longer windows are
+// counted at a coarser step and the count scaled back to SampleCountStep
+const maxSampleCountSteps = 1440
+
 // DefaultConfig returns default configuration
 func DefaultConfig(prometheusURL string) Config {
 	return Config{
@@ -31,6 +46,7 @@ func DefaultConfig(prometheusURL string) Config {
 		MaxConcurrency: 10,
 		RetryCount:     1,
 		RetryDelay:     100 * time.Millisecond,
+		CacheFreshness: 30 * time.Second,
 	}
 }
 
@@ -39,6 +55,7 @@ type Adapter struct {
 	config Config
 	client *http.Client
 	sem    *semaphore.Weighted
+	cache  *queryCache
 }
 
 // NewAdapter creates a new Prometheus adapter
@@ -48,22 +65,144 @@ func NewAdapter(config Config) *Adapter {
 		client: &http.Client{
 			Timeout: config.Timeout,
 		},
-		sem: semaphore.NewWeighted(config.MaxConcurrency),
+		sem:   semaphore.NewWeighted(config.MaxConcurrency),
+		cache: newQueryCache(config.CacheFreshness),
+	}
+}
+
+// QueryRatio implements the eval.MetricsAdapter interface
+// The good and total instant queries are evaluated at query.Time and issued concurrently
+// through the query cache, so a series shared by several SLOs is fetched once per cycle;
+// a failure of either cancels the other. With a sample count step the total series'
+// sample count is fetched alongside.
+func (a *Adapter) QueryRatio(ctx context.Context, query eval.RatioQuery) (eval.RatioResult, error) {
+	var good, total eval.SeriesValue
+	var samples *int
+	g, gctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		var err error
+		good, err = a.cachedInstant(gctx, query.Good, query.Window, query.Time)
+		if err != nil {
+			return fmt.Errorf("good: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		var err error
+		total, err = a.cachedInstant(gctx, query.Total, query.Window, query.Time)
+		if err != nil {
+			return fmt.Errorf("total: %w", err)
+		}
+		return nil
+	})
+
+	var maxSamples *int
+	if window, err := slo.ParseDuration(query.Window); err == nil && a.config.SampleCountStep > 0 && window >= a.config.SampleCountStep {
+		step, scale := sampleCountStep(window, a.config.SampleCountStep)
+		steps := int(window/step) * scale
+		maxSamples = &steps
+		g.Go(func() error {
+			count, err := a.cachedInstant(gctx, sampleCountQuery(query.Total, query.Window, step), query.Window, query.Time)
+			if err != nil {
+				return fmt.Errorf("sample count: %w", err)
+			}
+			n := int(count.Value) * scale
+			samples = &n
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return eval.RatioResult{}, err
 	}
+	if samples != nil {
+		total.SampleCount = samples
+		total.MaxSamples = maxSamples
+	}
+
+	return eval.RatioResult{
+		Window: query.Window,
+		Good:   good,
+		Total:  total,
+	}, nil
 }
 
-// QueryWindow implements the MetricsAdapter interface
-// It executes a Prometheus instant query with {{window}} substituted
-func (a *Adapter) QueryWindow(query string, window string) (eval.WindowMetrics, error) {
-	// Substitute {{window}} with actual window value
-	instantQuery := substituteWindow(query, window)
+// QueryStats implements the eval.QueryStatsReporter interface
+func (a *Adapter) QueryStats() eval.QueryStats {
+	return a.cache.stats()
+}
+
+// cachedInstant runs an instant query through the query cache, keyed by the substituted query
+func (a *Adapter) cachedInstant(ctx context.Context, query string, window string, at time.Time) (eval.SeriesValue, error) {
+	return a.cache.get(ctx, substituteWindow(query, window), at, func(ctx context.Context) (eval.SeriesValue, error) {
+		return a.QueryInstant(ctx, query, window, at)
+	})
+}
 
+// QueryInstant executes a Prometheus instant query with {{window}} substituted at the
+// given time (Prometheus' current time when zero) and sums the resulting series.
+// Instant queries carry no sample count.
+func (a *Adapter) QueryInstant(ctx context.Context, query string, window string, at time.Time) (eval.SeriesValue, error) {
+	result, err := a.instantQuery(ctx, substituteWindow(query, window), at)
+	if err != nil {
+		return eval.SeriesValue{}, err
+	}
+
+	return eval.SeriesValue{
+		Value:     extractScalarValue(result),
+		Timestamp: extractTimestamp(result),
+	}, nil
+}
+
+// QueryBreakdown implements the eval.BreakdownAdapter interface
+// The good and total instant queries are issued concurrently, bypassing the query cache,
+// and their series are summed per group of values of labels rather than overall.
+func (a *Adapter) QueryBreakdown(ctx context.Context, query eval.RatioQuery, labels []string) ([]eval.GroupRatio, error) {
+	var good, total *QueryResponse
+	g, gctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		var err error
+		good, err = a.instantQuery(gctx, substituteWindow(query.Good, query.Window), query.Time)
+		if err != nil {
+			return fmt.Errorf("good: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		var err error
+		total, err = a.instantQuery(gctx, substituteWindow(query.Total, query.Window), query.Time)
+		if err != nil {
+			return fmt.Errorf("total: %w", err)
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	groups := eval.NewRatioGroups(labels)
+	for _, result := range total.Data.Result {
+		groups.AddTotal(result.Metric, result.Value.Value())
+	}
+	for _, result := range good.Data.Result {
+		groups.AddGood(result.Metric, result.Value.Value())
+	}
+	return groups.Ratios(), nil
+}
+
+// instantQuery executes a substituted instant query with retry, honouring the concurrency limit
+func (a *Adapter) instantQuery(ctx context.Context, query string, at time.Time) (*QueryResponse, error) {
 	// Acquire semaphore to limit concurrency
-	ctx, cancel := context.WithTimeout(context.Background(), a.config.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeout)
 	defer cancel()
 
 	if err := a.sem.Acquire(ctx, 1); err != nil {
-		return eval.WindowMetrics{}, fmt.Errorf("semaphore acquire: %w", err)
+		return nil, fmt.Errorf("semaphore acquire: %w", err)
 	}
 	defer a.sem.Release(1)
 
@@ -71,39 +210,211 @@ func (a *Adapter) QueryWindow(query string, window string) (eval.WindowMetrics,
 	var lastErr error
 	for attempt := 0; attempt <= a.config.RetryCount; attempt++ {
 		if attempt > 0 {
-			time.Sleep(a.config.RetryDelay)
+			if err := sleepContext(ctx, a.config.RetryDelay); err != nil {
+				break
+			}
 		}
 
-		result, err := a.executeQuery(ctx, instantQuery)
+		result, err := a.executeQuery(ctx, query, at)
 		if err == nil {
-			// Extract scalar value from result
-			value := extractScalarValue(result)
-			timestamp := extractTimestamp(result)
-
-			return eval.WindowMetrics{
-				Window:        window,
-				Good:          value,
-				Total:         value, // For instant queries, good=total (caller queries separately)
-				DataTimestamp: timestamp,
-			}, nil
+			return result, nil
 		}
 
 		lastErr = err
 	}
 
-	return eval.WindowMetrics{}, fmt.Errorf("query failed after %d attempts: %w", a.config.RetryCount+1, lastErr)
+	return nil, fmt.Errorf("query failed after %d attempts: %w", a.config.RetryCount+1, lastErr)
 }
 
-// executeQuery performs a single Prometheus query
-func (a *Adapter) executeQuery(ctx context.Context, query string) (*QueryResponse, error) {
-	// Build query URL
-	queryURL := fmt.Sprintf("%s/api/v1/query", strings.TrimSuffix(a.config.URL, "/"))
+// QueryRange implements the eval.RangeAdapter interface
+// It executes a Prometheus range query covering the window ending at end (now when zero),
+// one point per step, with {{window}} substituted by the step. Series are summed per
+// timestamp under eval.AggregateSum; eval.AggregateSingle fails when the query returns
+// more than one series.
+func (a *Adapter) QueryRange(ctx context.Context, query string, window string, step string, end time.Time, aggregation eval.SeriesAggregation) ([]eval.Sample, error) {
+	windowDur, err := slo.ParseDuration(window)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window: %w", err)
+	}
+	stepDur, err := slo.ParseDuration(step)
+	if err != nil {
+		return nil, fmt.Errorf("invalid step: %w", err)
+	}
+	if stepDur <= 0 || stepDur > windowDur {
+		return nil, fmt.Errorf("step %s must be positive and <= window %s", step, window)
+	}
+
+	rangeQuery := substituteWindow(query, step)
+
+	// Align to step boundaries so each point summarises one complete step
+	if end.IsZero() {
+		end = time.Now()
+	}
+	end = end.Truncate(stepDur)
+	start := end.Add(-windowDur).Add(stepDur)
+
+	sums := make(map[int64]float64)
+	for chunkStart := start; !chunkStart.After(end); {
+		chunkEnd := chunkStart.Add(time.Duration(maxPointsPerQuery-1) * stepDur)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+
+		resp, err := a.queryRangeChunk(ctx, rangeQuery, chunkStart, chunkEnd, stepDur)
+		if err != nil {
+			return nil, err
+		}
+
+		if aggregation == eval.AggregateSingle && len(resp.Data.Result) > 1 {
+			return nil, fmt.Errorf("query returned %d series, expected a single series; aggregate it (e.g. avg or max)", len(resp.Data.Result))
+		}
+
+		for _, result := range resp.Data.Result {
+			for _, pair := range result.Values {
+				sums[pair.Timestamp().Unix()] += pair.Value()
+			}
+		}
+
+		chunkStart = chunkEnd.Add(stepDur)
+	}
 
+	samples := make([]eval.Sample, 0, len(sums))
+	for ts, value := range sums {
+		samples = append(samples, eval.Sample{
+			Timestamp: time.Unix(ts, 0),
+			Value:     value,
+		})
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Timestamp.Before(samples[j].Timestamp)
+	})
+
+	return samples, nil
+}
+
+// queryRangeChunk executes a single range query with retry, honouring the concurrency limit
+func (a *Adapter) queryRangeChunk(ctx context.Context, query string, start, end time.Time, step time.Duration) (*QueryResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeout)
+	defer cancel()
+
+	if err := a.sem.Acquire(ctx, 1); err != nil {
+		return nil, fmt.Errorf("semaphore acquire: %w", err)
+	}
+	defer a.sem.Release(1)
+
+	params := url.Values{}
+	params.Add("query", query)
+	params.Add("start", strconv.FormatInt(start.Unix(), 10))
+	params.Add("end", strconv.FormatInt(end.Unix(), 10))
+	params.Add("step", strconv.FormatInt(int64(step/time.Second), 10))
+
+	var lastErr error
+	for attempt := 0; attempt <= a.config.RetryCount; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, a.config.RetryDelay); err != nil {
+				break
+			}
+		}
+
+		result, err := a.doRequest(ctx, "/api/v1/query_range", params)
+		if err == nil {
+			return result, nil
+		}
+
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("range query failed after %d attempts: %w", a.config.RetryCount+1, lastErr)
+}
+
+// executeQuery performs a single Prometheus query
+func (a *Adapter) executeQuery(ctx context.Context, query string, at time.Time) (*QueryResponse, error) {
 	// Add query parameter
 	params := url.Values{}
 	params.Add("query", query)
+	if !at.IsZero() {
+		params.Add("time", strconv.FormatInt(at.Unix(), 10))
+	}
 
-	fullURL := queryURL + "?" + params.Encode()
+	return a.doRequest(ctx, "/api/v1/query", params)
+}
+
+// LabelValues implements the eval.LabelValuesAdapter interface
+// It lists the values of a label via /api/v1/label/<name>/values, restricted to
+// series matching the selector when one is given and to series with samples between
+// start and end when they are set; without a range Prometheus searches its whole retention.
+func (a *Adapter) LabelValues(ctx context.Context, label string, match string, start, end time.Time) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeout)
+	defer cancel()
+
+	if err := a.sem.Acquire(ctx, 1); err != nil {
+		return nil, fmt.Errorf("semaphore acquire: %w", err)
+	}
+	defer a.sem.Release(1)
+
+	params := url.Values{}
+	if match != "" {
+		params.Add("match[]", match)
+	}
+	if !start.IsZero() {
+		params.Add("start", strconv.FormatInt(start.Unix(), 10))
+	}
+	if !end.IsZero() {
+		params.Add("end", strconv.FormatInt(end.Unix(), 10))
+	}
+	path := "/api/v1/label/" + url.PathEscape(label) + "/values"
+
+	var lastErr error
+	for attempt := 0; attempt <= a.config.RetryCount; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, a.config.RetryDelay); err != nil {
+				break
+			}
+		}
+
+		body, err := a.doRawRequest(ctx, path, params)
+		if err == nil {
+			var result LabelValuesResponse
+			if err := json.Unmarshal(body, &result); err != nil {
+				return nil, fmt.Errorf("parse response: %w", err)
+			}
+			if result.Status != "success" {
+				return nil, fmt.Errorf("prometheus error: %s", result.Error)
+			}
+			return result.Data, nil
+		}
+
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("label values failed after %d attempts: %w", a.config.RetryCount+1, lastErr)
+}
+
+// doRequest performs a single GET against a Prometheus query endpoint
+func (a *Adapter) doRequest(ctx context.Context, path string, params url.Values) (*QueryResponse, error) {
+	body, err := a.doRawRequest(ctx, path, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse JSON response
+	var result QueryResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+
+	// Check Prometheus status
+	if result.Status != "success" {
+		return nil, fmt.Errorf("prometheus error: %s", result.Error)
+	}
+
+	return &result, nil
+}
+
+// doRawRequest performs a single GET against a Prometheus API endpoint and returns the body
+func (a *Adapter) doRawRequest(ctx context.Context, path string, params url.Values) ([]byte, error) {
+	// Build query URL
+	fullURL := strings.TrimSuffix(a.config.URL, "/") + path + "?" + params.Encode()
 
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
@@ -129,18 +440,38 @@ func (a *Adapter) executeQuery(ctx context.Context, query string) (*QueryRespons
 		return nil, fmt.Errorf("http status %d: %s", resp.StatusCode, string(body))
 	}
 
-	// Parse JSON response
-	var result QueryResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("parse response: %w", err)
+	return body, nil
+}
+
+// sleepContext waits for d or until ctx is done, returning ctx.Err() in the latter case
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
+}
 
-	// Check Prometheus status
-	if result.Status != "success" {
-		return nil, fmt.Errorf("prometheus error: %s", result.Error)
+// sampleCountStep returns the step a window's samples are counted at: the configured
+// step, or a whole multiple of it (scale) keeping the subquery within maxSampleCountSteps
+func sampleCountStep(window, step time.Duration) (time.Duration, int) {
+	scale := 1
+	if steps := int(window / step); steps > maxSampleCountSteps {
+		scale = (steps + maxSampleCountSteps - 1) / maxSampleCountSteps
 	}
+	return step * time.Duration(scale), scale
+}
 
-	return &result, nil
+// sampleCountQuery counts the steps of the window at which query, evaluated with
+// {{window}} set to the step, returns data. The series are summed first so a query
+// returning several series counts each step once.
+func sampleCountQuery(query string, window string, step time.Duration) string {
+	stepStr := slo.FormatDuration(step)
+	return fmt.Sprintf("count_over_time(sum(%s)[%s:%s])", substituteWindow(query, stepStr), window, stepStr)
 }
 
 // substituteWindow replaces {{window}} placeholder with actual window value
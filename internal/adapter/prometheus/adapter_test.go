@@ -1,25 +1,34 @@
 package prometheus
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/eval"
 )
 
-func TestAdapter_QueryWindow(t *testing.T) {
+func TestAdapter_QueryRatio(t *testing.T) {
 	// Create a mock Prometheus server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		query := r.URL.Query().Get("query")
 
 		// Check that {{window}} was substituted
-		if query == "rate(requests[{{window}}])" {
+		if query == "rate(good[{{window}}])" || query == "rate(total[{{window}}])" {
 			t.Error("window template not substituted")
 		}
 
+		value := "100.5"
+		if query == "rate(total[5m])" {
+			value = "120"
+		}
+
 		// Return mock response
 		resp := QueryResponse{
 			Status: "success",
@@ -28,7 +37,7 @@ func TestAdapter_QueryWindow(t *testing.T) {
 				Result: []VectorResult{
 					{
 						Metric: map[string]string{"job": "test"},
-						Value:  SamplePair{float64(time.Now().Unix()), "100.5"},
+						Value:  SamplePair{float64(time.Now().Unix()), value},
 					},
 				},
 			},
@@ -44,7 +53,11 @@ func TestAdapter_QueryWindow(t *testing.T) {
 	adapter := NewAdapter(config)
 
 	// Test query
-	result, err := adapter.QueryWindow("rate(requests[{{window}}])", "5m")
+	result, err := adapter.QueryRatio(context.Background(), eval.RatioQuery{
+		Good:   "rate(good[{{window}}])",
+		Total:  "rate(total[{{window}}])",
+		Window: "5m",
+	})
 	if err != nil {
 		t.Fatalf("query failed: %v", err)
 	}
@@ -53,12 +66,43 @@ func TestAdapter_QueryWindow(t *testing.T) {
 		t.Errorf("expected window=5m, got %s", result.Window)
 	}
 
-	if result.Good != 100.5 {
-		t.Errorf("expected value=100.5, got %f", result.Good)
+	if result.Good.Value != 100.5 {
+		t.Errorf("expected good=100.5, got %f", result.Good.Value)
+	}
+
+	if result.Total.Value != 120 {
+		t.Errorf("expected total=120, got %f", result.Total.Value)
 	}
 
-	if result.DataTimestamp == nil {
-		t.Error("expected timestamp to be set")
+	if result.Good.Timestamp == nil || result.Total.Timestamp == nil {
+		t.Error("expected both series timestamps to be set")
+	}
+
+	if result.Good.SampleCount != nil {
+		t.Error("expected no sample count from instant queries")
+	}
+}
+
+func TestAdapter_QueryRatioError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") == "broken_total" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(QueryResponse{Status: "success", Data: QueryData{ResultType: "vector"}})
+	}))
+	defer server.Close()
+
+	config := DefaultConfig(server.URL)
+	config.RetryCount = 0
+	adapter := NewAdapter(config)
+
+	_, err := adapter.QueryRatio(context.Background(), eval.RatioQuery{Good: "ok_good", Total: "broken_total", Window: "5m"})
+	if err == nil {
+		t.Fatal("expected error when the total query fails")
+	}
+	if !strings.Contains(err.Error(), "total") {
+		t.Errorf("expected error to name the failing series, got %v", err)
 	}
 }
 
@@ -131,13 +175,13 @@ func TestAdapter_Retry(t *testing.T) {
 	config.RetryDelay = 10 * time.Millisecond
 	adapter := NewAdapter(config)
 
-	result, err := adapter.QueryWindow("test_metric", "5m")
+	result, err := adapter.QueryInstant(context.Background(), "test_metric", "5m", time.Time{})
 	if err != nil {
 		t.Fatalf("expected success after retry, got error: %v", err)
 	}
 
-	if result.Good != 42 {
-		t.Errorf("expected value=42, got %f", result.Good)
+	if result.Value != 42 {
+		t.Errorf("expected value=42, got %f", result.Value)
 	}
 
 	if atomic.LoadInt32(&attempts) != 2 {
@@ -158,7 +202,7 @@ func TestAdapter_Timeout(t *testing.T) {
 	config.RetryCount = 0
 	adapter := NewAdapter(config)
 
-	_, err := adapter.QueryWindow("test_metric", "5m")
+	_, err := adapter.QueryInstant(context.Background(), "test_metric", "5m", time.Time{})
 	if err == nil {
 		t.Error("expected timeout error, got nil")
 	}
@@ -177,7 +221,7 @@ func TestAdapter_PrometheusError(t *testing.T) {
 	config := DefaultConfig(server.URL)
 	adapter := NewAdapter(config)
 
-	_, err := adapter.QueryWindow("invalid_query", "5m")
+	_, err := adapter.QueryInstant(context.Background(), "invalid_query", "5m", time.Time{})
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
@@ -226,7 +270,7 @@ func TestAdapter_Concurrency(t *testing.T) {
 	done := make(chan error, 10)
 	for i := 0; i < 10; i++ {
 		go func(id int) {
-			_, err := adapter.QueryWindow(fmt.Sprintf("metric_%d", id), "5m")
+			_, err := adapter.QueryInstant(context.Background(), fmt.Sprintf("metric_%d", id), "5m", time.Time{})
 			done <- err
 		}(i)
 	}
@@ -262,16 +306,16 @@ func TestAdapter_ZeroResults(t *testing.T) {
 	config := DefaultConfig(server.URL)
 	adapter := NewAdapter(config)
 
-	result, err := adapter.QueryWindow("missing_metric", "5m")
+	result, err := adapter.QueryInstant(context.Background(), "missing_metric", "5m", time.Time{})
 	if err != nil {
 		t.Fatalf("query failed: %v", err)
 	}
 
-	if result.Good != 0 {
-		t.Errorf("expected value=0 for no results, got %f", result.Good)
+	if result.Value != 0 {
+		t.Errorf("expected value=0 for no results, got %f", result.Value)
 	}
 
-	if result.DataTimestamp != nil {
+	if result.Timestamp != nil {
 		t.Error("expected nil timestamp for no results")
 	}
 }
@@ -326,3 +370,255 @@ func TestExtractScalarValue(t *testing.T) {
 		})
 	}
 }
+
+func TestAdapter_QueryRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query_range" {
+			t.Errorf("expected range query path, got %s", r.URL.Path)
+		}
+		if q := r.URL.Query().Get("query"); q != "avg_over_time(up[1m])" {
+			t.Errorf("expected window substituted with step, got %q", q)
+		}
+		if step := r.URL.Query().Get("step"); step != "60" {
+			t.Errorf("expected step=60, got %s", step)
+		}
+
+		resp := QueryResponse{
+			Status: "success",
+			Data: QueryData{
+				ResultType: "matrix",
+				Result: []VectorResult{
+					{Values: []SamplePair{{float64(120), "0.5"}, {float64(60), "1"}}},
+					{Values: []SamplePair{{float64(120), "0.25"}}},
+				},
+			},
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(DefaultConfig(server.URL))
+
+	samples, err := adapter.QueryRange(context.Background(), "avg_over_time(up[{{window}}])", "5m", "1m", time.Time{}, eval.AggregateSum)
+	if err != nil {
+		t.Fatalf("range query failed: %v", err)
+	}
+
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(samples))
+	}
+
+	// Sorted by timestamp, series summed per timestamp
+	if samples[0].Value != 1 || samples[1].Value != 0.75 {
+		t.Errorf("expected values [1 0.75], got [%v %v]", samples[0].Value, samples[1].Value)
+	}
+
+	// Several series cannot be combined when a single series is required
+	_, err = adapter.QueryRange(context.Background(), "avg_over_time(up[{{window}}])", "5m", "1m", time.Time{}, eval.AggregateSingle)
+	if err == nil || !strings.Contains(err.Error(), "returned 2 series") {
+		t.Errorf("expected an error naming the series count, got %v", err)
+	}
+}
+
+func TestAdapter_LabelValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/label/customer/values" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if match := r.URL.Query().Get("match[]"); match != `up{job="api"}` {
+			t.Errorf("expected match selector, got %q", match)
+		}
+		if start, end := r.URL.Query().Get("start"), r.URL.Query().Get("end"); start != "1767222000" || end != "1767225600" {
+			t.Errorf("expected start=1767222000 and end=1767225600, got start=%q end=%q", start, end)
+		}
+		json.NewEncoder(w).Encode(LabelValuesResponse{
+			Status: "success",
+			Data:   []string{"acme", "globex"},
+		})
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(DefaultConfig(server.URL))
+
+	end := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	values, err := adapter.LabelValues(context.Background(), "customer", `up{job="api"}`, end.Add(-time.Hour), end)
+	if err != nil {
+		t.Fatalf("label values failed: %v", err)
+	}
+	if len(values) != 2 || values[0] != "acme" || values[1] != "globex" {
+		t.Errorf("expected [acme globex], got %v", values)
+	}
+}
+
+func TestAdapter_ContextCancellation(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	config := DefaultConfig(server.URL)
+	config.RetryCount = 3
+	config.RetryDelay = time.Second
+	adapter := NewAdapter(config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := adapter.QueryInstant(ctx, "slow_metric", "5m", time.Time{})
+	if err == nil {
+		t.Fatal("expected error after cancellation, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected cancellation to abort retries promptly, took %s", elapsed)
+	}
+}
+
+func TestAdapter_QueryAtEvaluationTime(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("time"); got != fmt.Sprint(at.Unix()) {
+			t.Errorf("expected time=%d, got %q", at.Unix(), got)
+		}
+		json.NewEncoder(w).Encode(QueryResponse{
+			Status: "success",
+			Data: QueryData{
+				ResultType: "vector",
+				Result:     []VectorResult{{Value: SamplePair{float64(at.Unix()), "7"}}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(DefaultConfig(server.URL))
+
+	result, err := adapter.QueryRatio(context.Background(), eval.RatioQuery{Good: "good", Total: "total", Window: "1h", Time: at})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	if result.Good.Timestamp == nil || !result.Good.Timestamp.Equal(at) {
+		t.Errorf("expected sample timestamp %s, got %v", at, result.Good.Timestamp)
+	}
+}
+
+func TestAdapter_QueryRatioSampleCount(t *testing.T) {
+	const countQuery = "count_over_time(sum(increase(total[5m]))[1h:5m])"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := "100"
+		if r.URL.Query().Get("query") == countQuery {
+			value = "12"
+		}
+		json.NewEncoder(w).Encode(QueryResponse{
+			Status: "success",
+			Data: QueryData{
+				ResultType: "vector",
+				Result:     []VectorResult{{Value: SamplePair{float64(time.Now().Unix()), value}}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	config := DefaultConfig(server.URL)
+	config.SampleCountStep = 5 * time.Minute
+	adapter := NewAdapter(config)
+
+	result, err := adapter.QueryRatio(context.Background(), eval.RatioQuery{
+		Good:   "increase(good[{{window}}])",
+		Total:  "increase(total[{{window}}])",
+		Window: "1h",
+	})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	if result.Total.Value != 100 {
+		t.Errorf("expected total=100, got %f", result.Total.Value)
+	}
+	if result.Total.SampleCount == nil || *result.Total.SampleCount != 12 {
+		t.Errorf("expected total sample count 12, got %v", result.Total.SampleCount)
+	}
+	if result.Total.MaxSamples == nil || *result.Total.MaxSamples != 12 {
+		t.Errorf("expected at most 12 samples, got %v", result.Total.MaxSamples)
+	}
+	if result.Good.SampleCount != nil {
+		t.Error("expected no sample count for the good series")
+	}
+}
+
+func TestSampleCountStep(t *testing.T) {
+	tests := []struct {
+		window        time.Duration
+		expectedStep  time.Duration
+		expectedScale int
+	}{
+		{window: 5 * time.Minute, expectedStep: time.Minute, expectedScale: 1},
+		{window: 24 * time.Hour, expectedStep: time.Minute, expectedScale: 1},
+		{window: 7 * 24 * time.Hour, expectedStep: 7 * time.Minute, expectedScale: 7},
+		{window: 30 * 24 * time.Hour, expectedStep: 30 * time.Minute, expectedScale: 30},
+	}
+
+	for _, tt := range tests {
+		step, scale := sampleCountStep(tt.window, time.Minute)
+		if step != tt.expectedStep || scale != tt.expectedScale {
+			t.Errorf("window %s: expected step %s (scale %d), got %s (scale %d)",
+				tt.window, tt.expectedStep, tt.expectedScale, step, scale)
+		}
+	}
+}
+
+func TestAdapter_QueryBreakdown(t *testing.T) {
+	series := func(route, code, value string) VectorResult {
+		return VectorResult{
+			Metric: map[string]string{"route": route, "code": code},
+			Value:  SamplePair{float64(time.Now().Unix()), value},
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result := []VectorResult{series("/checkout", "200", "90"), series("/search", "200", "50")}
+		if r.URL.Query().Get("query") == "sum by (route, code) (increase(total[1h]))" {
+			result = []VectorResult{
+				series("/checkout", "200", "90"),
+				series("/checkout", "500", "30"),
+				series("/search", "200", "50"),
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(QueryResponse{
+			Status: "success",
+			Data:   QueryData{ResultType: "vector", Result: result},
+		})
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(DefaultConfig(server.URL))
+
+	groups, err := adapter.QueryBreakdown(context.Background(), eval.RatioQuery{
+		Good:   "sum by (route, code) (increase(good[{{window}}]))",
+		Total:  "sum by (route, code) (increase(total[{{window}}]))",
+		Window: "1h",
+	}, []string{"route"})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	expected := map[string][2]float64{"/checkout": {90, 120}, "/search": {50, 50}}
+	if len(groups) != len(expected) {
+		t.Fatalf("expected %d groups, got %+v", len(expected), groups)
+	}
+	for _, group := range groups {
+		want, ok := expected[group.Labels["route"]]
+		if !ok || group.Good != want[0] || group.Total != want[1] {
+			t.Errorf("unexpected group %+v", group)
+		}
+	}
+}
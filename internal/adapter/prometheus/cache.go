@@ -0,0 +1,138 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/eval"
+	"golang.org/x/sync/singleflight"
+)
+
+// queryCache deduplicates identical substituted queries across SLOs and keeps their
+// results for the freshness window. Results are bucketed by the evaluation time
+// truncated to the freshness window, so every SLO evaluated in the same cycle sees
+// the same value and the whole cache turns over at bucket boundaries.
+type queryCache struct {
+	freshness time.Duration
+	now       func() time.Time
+	group     singleflight.Group
+
+	mu      sync.Mutex
+	bucket  time.Time
+	entries map[string]eval.SeriesValue
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+func newQueryCache(freshness time.Duration) *queryCache {
+	return &queryCache{
+		freshness: freshness,
+		now:       time.Now,
+		entries:   make(map[string]eval.SeriesValue),
+	}
+}
+
+// get returns the cached value for key in the bucket of the evaluation time at (now when
+// zero) or runs fetch once for all concurrent callers. With a zero freshness nothing is
+// retained, but concurrent callers still share a query.
+func (c *queryCache) get(ctx context.Context, key string, at time.Time, fetch func(context.Context) (eval.SeriesValue, error)) (eval.SeriesValue, error) {
+	if at.IsZero() {
+		at = c.now()
+	}
+	bucket := c.bucketFor(at)
+
+	if value, ok := c.lookup(key, bucket); ok {
+		c.hits.Add(1)
+		return value, nil
+	}
+
+	flightKey := key + "\x00" + bucket.Format(time.RFC3339Nano)
+	for {
+		leader := false
+		ch := c.group.DoChan(flightKey, func() (interface{}, error) {
+			leader = true
+			c.misses.Add(1)
+			value, err := fetch(ctx)
+			if err == nil {
+				c.store(key, bucket, value)
+			}
+			return value, err
+		})
+
+		select {
+		case <-ctx.Done():
+			return eval.SeriesValue{}, ctx.Err()
+		case res := <-ch:
+			if !leader {
+				// The leader's context was cancelled, not ours: query again
+				if res.Err != nil && errors.Is(res.Err, context.Canceled) && ctx.Err() == nil {
+					continue
+				}
+				c.hits.Add(1)
+			}
+			if res.Err != nil {
+				return eval.SeriesValue{}, res.Err
+			}
+			return res.Val.(eval.SeriesValue), nil
+		}
+	}
+}
+
+// bucketFor aligns t to the freshness window. Without a freshness window only queries
+// for the same second, the resolution of the query time sent to Prometheus, are shared.
+func (c *queryCache) bucketFor(t time.Time) time.Time {
+	if c.freshness <= 0 {
+		return t.Truncate(time.Second)
+	}
+	return t.Truncate(c.freshness)
+}
+
+func (c *queryCache) lookup(key string, bucket time.Time) (eval.SeriesValue, bool) {
+	if c.freshness <= 0 {
+		return eval.SeriesValue{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !bucket.Equal(c.bucket) {
+		return eval.SeriesValue{}, false
+	}
+	value, ok := c.entries[key]
+	return value, ok
+}
+
+func (c *queryCache) store(key string, bucket time.Time, value eval.SeriesValue) {
+	if c.freshness <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if bucket.Before(c.bucket) {
+		return // a slow query from a previous cycle
+	}
+	if bucket.After(c.bucket) {
+		c.bucket = bucket
+		c.entries = make(map[string]eval.SeriesValue)
+	}
+	c.entries[key] = value
+}
+
+// stats returns the hit and miss counters and the number of cached results
+func (c *queryCache) stats() eval.QueryStats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+
+	return eval.QueryStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: entries,
+	}
+}
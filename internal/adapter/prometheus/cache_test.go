@@ -0,0 +1,153 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/eval"
+)
+
+// countingServer answers every instant query with 1 and counts requests per query
+func countingServer(t *testing.T, delay time.Duration) (*httptest.Server, func(string) int32) {
+	t.Helper()
+
+	var mu sync.Mutex
+	counts := make(map[string]*int32)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		mu.Lock()
+		if counts[query] == nil {
+			counts[query] = new(int32)
+		}
+		atomic.AddInt32(counts[query], 1)
+		mu.Unlock()
+
+		time.Sleep(delay)
+		json.NewEncoder(w).Encode(QueryResponse{
+			Status: "success",
+			Data: QueryData{
+				ResultType: "vector",
+				Result:     []VectorResult{{Value: SamplePair{float64(time.Now().Unix()), "1"}}},
+			},
+		})
+	}))
+
+	count := func(query string) int32 {
+		mu.Lock()
+		defer mu.Unlock()
+		if counts[query] == nil {
+			return 0
+		}
+		return atomic.LoadInt32(counts[query])
+	}
+	return server, count
+}
+
+func TestQueryCache_DeduplicatesSharedTotal(t *testing.T) {
+	server, count := countingServer(t, 200*time.Millisecond)
+	defer server.Close()
+
+	config := DefaultConfig(server.URL)
+	config.CacheFreshness = 0 // only in-flight deduplication
+	adapter := NewAdapter(config)
+	now := time.Now()
+	adapter.cache.now = func() time.Time { return now }
+
+	// Three SLOs share the same total query
+	var wg sync.WaitGroup
+	for _, good := range []string{"good_a[{{window}}]", "good_b[{{window}}]", "good_c[{{window}}]"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := adapter.QueryRatio(context.Background(), eval.RatioQuery{Good: good, Total: "total[{{window}}]", Window: "30d"})
+			if err != nil {
+				t.Errorf("query failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := count("total[30d]"); got != 1 {
+		t.Errorf("expected shared total to be queried once, got %d", got)
+	}
+
+	stats := adapter.QueryStats()
+	if stats.Misses != 4 || stats.Hits != 2 {
+		t.Errorf("expected 4 misses and 2 hits, got %+v", stats)
+	}
+	if stats.Entries != 0 {
+		t.Errorf("expected nothing retained with zero freshness, got %d entries", stats.Entries)
+	}
+}
+
+func TestQueryCache_Freshness(t *testing.T) {
+	server, count := countingServer(t, 0)
+	defer server.Close()
+
+	config := DefaultConfig(server.URL)
+	config.CacheFreshness = time.Minute
+	adapter := NewAdapter(config)
+
+	now := time.Date(2026, 1, 1, 12, 0, 10, 0, time.UTC)
+	adapter.cache.now = func() time.Time { return now }
+
+	query := eval.RatioQuery{Good: "good[{{window}}]", Total: "total[{{window}}]", Window: "1h"}
+	for i := 0; i < 3; i++ {
+		if _, err := adapter.QueryRatio(context.Background(), query); err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+	}
+
+	if got := count("good[1h]"); got != 1 {
+		t.Errorf("expected one request within the freshness window, got %d", got)
+	}
+
+	// Crossing the bucket boundary expires every entry
+	now = now.Add(time.Minute)
+	if _, err := adapter.QueryRatio(context.Background(), query); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	if got := count("good[1h]"); got != 2 {
+		t.Errorf("expected a new request in the next cycle, got %d", got)
+	}
+
+	stats := adapter.QueryStats()
+	if stats.Hits != 4 || stats.Misses != 4 || stats.Entries != 2 {
+		t.Errorf("expected 4 hits, 4 misses and 2 entries, got %+v", stats)
+	}
+}
+
+func TestQueryCache_ErrorsNotCached(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(QueryResponse{Status: "success", Data: QueryData{ResultType: "vector"}})
+	}))
+	defer server.Close()
+
+	config := DefaultConfig(server.URL)
+	config.RetryCount = 0
+	adapter := NewAdapter(config)
+
+	fetch := func(ctx context.Context) (eval.SeriesValue, error) {
+		return adapter.QueryInstant(ctx, "metric", "5m", time.Time{})
+	}
+
+	if _, err := adapter.cache.get(context.Background(), "metric", time.Time{}, fetch); err == nil {
+		t.Fatal("expected first query to fail")
+	}
+	if _, err := adapter.cache.get(context.Background(), "metric", time.Time{}, fetch); err != nil {
+		t.Fatalf("expected failed result not to be cached, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package prometheus_test
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -90,7 +91,7 @@ func TestPrometheusAdapter_Integration(t *testing.T) {
 
 	// Evaluate SLO
 	now := time.Now()
-	evalResult, err := evaluator.Evaluate(sloSpec, now)
+	evalResult, err := evaluator.Evaluate(context.Background(), sloSpec, now)
 	if err != nil {
 		t.Fatalf("evaluation failed: %v", err)
 	}
@@ -160,13 +161,23 @@ func TestPrometheusAdapter_QueryFailure_ReturnsWarn(t *testing.T) {
 
 	evaluator := eval.NewEvaluator(adapter)
 
-	// Evaluation should fail when Prometheus is unavailable
-	_, err := evaluator.Evaluate(sloSpec, time.Now())
-	if err == nil {
-		t.Error("expected error when Prometheus is unavailable, got nil")
+	// Query failures are reported per window rather than failing the evaluation
+	result, err := evaluator.Evaluate(context.Background(), sloSpec, time.Now())
+	if err != nil {
+		t.Fatalf("expected query errors in the result, got error: %v", err)
+	}
+
+	for _, window := range []string{"5m", "1h", "30d"} {
+		if _, ok := result.QueryErrors[window]; !ok {
+			t.Errorf("expected query error for window %s, got %v", window, result.QueryErrors)
+		}
+	}
+
+	// The policy engine turns them into a WARN
+	gateResult := policy.NewEngine().Evaluate(sloSpec, result)
+	if gateResult.Decision != policy.DecisionWARN || !gateResult.QueryFailed {
+		t.Errorf("expected WARN for query failure, got %s (reasons: %v)", gateResult.Decision, gateResult.Reasons)
 	}
 
-	// In a real system, this would trigger a WARN decision
-	// For now, we verify that query failures are properly propagated
-	t.Logf("✓ Query failure properly detected: %v", err)
+	t.Logf("✓ Query failure yields %s: %v", gateResult.Decision, gateResult.Reasons)
 }
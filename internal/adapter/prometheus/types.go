@@ -12,16 +12,25 @@ type QueryResponse struct {
 	Error  string    `json:"error,omitempty"`
 }
 
+// LabelValuesResponse represents a Prometheus label values API response
+type LabelValuesResponse struct {
+	Status string   `json:"status"`
+	Data   []string `json:"data"`
+	Error  string   `json:"error,omitempty"`
+}
+
 // QueryData contains the query result data
 type QueryData struct {
 	ResultType string         `json:"resultType"`
 	Result     []VectorResult `json:"result"`
 }
 
-// VectorResult represents a single result from an instant vector query
+// VectorResult represents a single result from an instant vector query.
+// For range (matrix) queries, Values holds the series samples instead of Value.
 type VectorResult struct {
 	Metric map[string]string `json:"metric"`
 	Value  SamplePair        `json:"value"`
+	Values []SamplePair      `json:"values,omitempty"`
 }
 
 // SamplePair is [timestamp, value]
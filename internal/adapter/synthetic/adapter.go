@@ -1,13 +1,16 @@
 package synthetic
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/samijaber1/aegis-slo/internal/eval"
+	"github.com/samijaber1/aegis-slo/internal/slo"
 )
 
 // MetricFixture represents a metric fixture file format
@@ -20,18 +23,55 @@ type WindowData struct {
 	Good          float64    `json:"good"`
 	Total         float64    `json:"total"`
 	DataTimestamp *time.Time `json:"dataTimestamp,omitempty"`
+	Samples       *int       `json:"samples,omitempty"` // reported as the sample count of both series
+	Slices        []float64  `json:"slices,omitempty"`  // per-step values for range queries
+	Breakdown     []Group    `json:"breakdown,omitempty"`
+}
+
+// Group holds the metrics of one group of label values for breakdown queries
+type Group struct {
+	Labels map[string]string `json:"labels"`
+	Good   float64           `json:"good"`
+	Total  float64           `json:"total"`
 }
 
 // Adapter is a synthetic metrics adapter that reads from JSON fixtures
 type Adapter struct {
-	fixtures map[string]*MetricFixture
+	fixtures    map[string]*MetricFixture
+	labelValues map[string][]string
+	mu          sync.RWMutex
 }
 
 // NewAdapter creates a new synthetic adapter
 func NewAdapter() *Adapter {
 	return &Adapter{
-		fixtures: make(map[string]*MetricFixture),
+		fixtures:    make(map[string]*MetricFixture),
+		labelValues: make(map[string][]string),
+	}
+}
+
+// SetLabelValues sets the values returned for a label (useful for testing)
+func (a *Adapter) SetLabelValues(label string, values []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.labelValues[label] = values
+}
+
+// LabelValues implements the LabelValuesAdapter interface
+// The match selector and time range are ignored.
+func (a *Adapter) LabelValues(ctx context.Context, label string, match string, start, end time.Time) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	values, exists := a.labelValues[label]
+	if !exists {
+		return nil, fmt.Errorf("label not found: %s", label)
 	}
+	return append([]string(nil), values...), nil
 }
 
 // LoadFixture loads a metric fixture from a JSON file
@@ -46,42 +86,136 @@ func (a *Adapter) LoadFixture(name string, path string) error {
 		return fmt.Errorf("failed to parse fixture: %w", err)
 	}
 
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.fixtures[name] = &fixture
 	return nil
 }
 
 // SetFixture directly sets a fixture (useful for testing)
 func (a *Adapter) SetFixture(name string, fixture *MetricFixture) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.fixtures[name] = fixture
 }
 
-// QueryWindow implements the MetricsAdapter interface
-// Query format: "fixture:name" where name is the fixture identifier
-func (a *Adapter) QueryWindow(query string, window string) (eval.WindowMetrics, error) {
-	// Parse query to extract fixture name
+// QueryRatio implements the MetricsAdapter interface
+// Each query names a fixture ("fixture:name" or just the name): good is read from the
+// good query's fixture and total from the total query's fixture.
+func (a *Adapter) QueryRatio(ctx context.Context, query eval.RatioQuery) (eval.RatioResult, error) {
+	if err := ctx.Err(); err != nil {
+		return eval.RatioResult{}, err
+	}
+
+	goodData, err := a.windowData(query.Good, query.Window)
+	if err != nil {
+		return eval.RatioResult{}, err
+	}
+	totalData, err := a.windowData(query.Total, query.Window)
+	if err != nil {
+		return eval.RatioResult{}, err
+	}
+
+	return eval.RatioResult{
+		Window: query.Window,
+		Good: eval.SeriesValue{
+			Value:       goodData.Good,
+			SampleCount: goodData.Samples,
+			Timestamp:   goodData.DataTimestamp,
+		},
+		Total: eval.SeriesValue{
+			Value:       totalData.Total,
+			SampleCount: totalData.Samples,
+			Timestamp:   totalData.DataTimestamp,
+		},
+	}, nil
+}
+
+// QueryBreakdown implements the BreakdownAdapter interface
+// Groups come from the fixture window's breakdown, good from the good query's fixture
+// and total from the total query's, summed per group of values of labels.
+func (a *Adapter) QueryBreakdown(ctx context.Context, query eval.RatioQuery, labels []string) ([]eval.GroupRatio, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	goodData, err := a.windowData(query.Good, query.Window)
+	if err != nil {
+		return nil, err
+	}
+	totalData, err := a.windowData(query.Total, query.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := eval.NewRatioGroups(labels)
+	for _, g := range totalData.Breakdown {
+		groups.AddTotal(g.Labels, g.Total)
+	}
+	for _, g := range goodData.Breakdown {
+		groups.AddGood(g.Labels, g.Good)
+	}
+	return groups.Ratios(), nil
+}
+
+// windowData looks up the fixture window a query refers to
+func (a *Adapter) windowData(query string, window string) (WindowData, error) {
 	fixtureName := a.parseQuery(query)
 	if fixtureName == "" {
-		return eval.WindowMetrics{}, fmt.Errorf("invalid query format: %s", query)
+		return WindowData{}, fmt.Errorf("invalid query format: %s", query)
 	}
 
-	// Get fixture
+	a.mu.RLock()
 	fixture, exists := a.fixtures[fixtureName]
+	a.mu.RUnlock()
 	if !exists {
-		return eval.WindowMetrics{}, fmt.Errorf("fixture not found: %s", fixtureName)
+		return WindowData{}, fmt.Errorf("fixture not found: %s", fixtureName)
 	}
 
-	// Get window data
 	windowData, exists := fixture.Windows[window]
 	if !exists {
-		return eval.WindowMetrics{}, fmt.Errorf("window not found in fixture: %s", window)
+		return WindowData{}, fmt.Errorf("window not found in fixture: %s", window)
 	}
 
-	return eval.WindowMetrics{
-		Window:        window,
-		Good:          windowData.Good,
-		Total:         windowData.Total,
-		DataTimestamp: windowData.DataTimestamp,
-	}, nil
+	return windowData, nil
+}
+
+// QueryRange implements the RangeAdapter interface
+// Returns the fixture's slice values for the window, oldest first, spaced by step
+// and ending at the window's data timestamp (or end, or now, when unset). Fixtures hold
+// a single series, so every aggregation returns it as is.
+func (a *Adapter) QueryRange(ctx context.Context, query string, window string, step string, end time.Time, aggregation eval.SeriesAggregation) ([]eval.Sample, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	windowData, err := a.windowData(query, window)
+	if err != nil {
+		return nil, err
+	}
+
+	stepDur, err := slo.ParseDuration(step)
+	if err != nil {
+		return nil, fmt.Errorf("invalid step: %w", err)
+	}
+
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if windowData.DataTimestamp != nil {
+		end = *windowData.DataTimestamp
+	}
+
+	samples := make([]eval.Sample, len(windowData.Slices))
+	for i, value := range windowData.Slices {
+		offset := time.Duration(len(windowData.Slices)-1-i) * stepDur
+		samples[i] = eval.Sample{
+			Timestamp: end.Add(-offset),
+			Value:     value,
+		}
+	}
+
+	return samples, nil
 }
 
 // parseQuery extracts the fixture name from a query string
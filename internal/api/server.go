@@ -3,17 +3,31 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/samijaber1/aegis-slo/internal/eval"
+	"github.com/samijaber1/aegis-slo/internal/policy"
 	"github.com/samijaber1/aegis-slo/internal/scheduler"
+	"github.com/samijaber1/aegis-slo/internal/slo"
 	"github.com/samijaber1/aegis-slo/internal/storage"
 )
 
+// Horizons of GET /v1/freezes
+const (
+	defaultFreezeHorizon = 7 * 24 * time.Hour
+	maxFreezeHorizon     = 366 * 24 * time.Hour
+)
+
+// maxOverrideDuration bounds how far ahead a decision override may expire
+const maxOverrideDuration = 7 * 24 * time.Hour
+
 // Server is the HTTP API server
 type Server struct {
 	scheduler *scheduler.Scheduler
@@ -45,6 +59,17 @@ func NewServer(sched *scheduler.Scheduler, addr string) *Server {
 	// Audit endpoint
 	mux.HandleFunc("/v1/audit", s.handleAudit)
 
+	// Change freeze endpoint
+	mux.HandleFunc("/v1/freezes", s.handleFreezes)
+
+	// Override endpoints
+	mux.HandleFunc("/v1/overrides", s.handleOverrides)
+	mux.HandleFunc("/v1/overrides/events", s.handleOverrideEvents)
+
+	// Admin endpoints
+	mux.HandleFunc("/v1/admin/reload", s.handleReload)
+	mux.HandleFunc("/v1/admin/query-cache", s.handleQueryCache)
+
 	s.server = &http.Server{
 		Addr:         addr,
 		Handler:      loggingMiddleware(mux),
@@ -126,9 +151,11 @@ func (s *Server) handleSLOList(w http.ResponseWriter, r *http.Request) {
 	for _, sloWithFile := range slos {
 		summaries = append(summaries, SLOSummary{
 			ID:          sloWithFile.SLO.Metadata.ID,
+			Kind:        sloWithFile.SLO.Kind,
 			Service:     sloWithFile.SLO.Metadata.Service,
 			Environment: sloWithFile.SLO.Spec.Environment,
 			Objective:   sloWithFile.SLO.Spec.Objective,
+			Parent:      sloWithFile.Parent,
 		})
 	}
 
@@ -182,6 +209,7 @@ func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
 	slos := s.scheduler.GetSLOs()
 	cache := s.scheduler.GetCache()
 
+	now := time.Now()
 	matchingSLOs := []string{}
 	decisions := make(map[string]string)
 	var lastUpdated time.Time
@@ -192,7 +220,7 @@ func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
 			matchingSLOs = append(matchingSLOs, id)
 
 			if state, ok := cache.Get(id); ok {
-				decisions[id] = string(state.GateResult.Decision)
+				decisions[id] = string(s.scheduler.ApplyFreezes(id, state.GateResult, now).Decision)
 				if state.UpdatedAt.After(lastUpdated) {
 					lastUpdated = state.UpdatedAt
 				}
@@ -234,7 +262,7 @@ func (s *Server) handleGateDecision(w http.ResponseWriter, r *http.Request) {
 
 	// Force fresh evaluation if requested
 	if req.ForceFresh {
-		if err := s.scheduler.EvaluateNow(req.SLOID); err != nil {
+		if err := s.scheduler.EvaluateNow(r.Context(), req.SLOID); err != nil {
 			respondError(w, http.StatusInternalServerError, fmt.Sprintf("evaluation failed: %v", err))
 			return
 		}
@@ -248,17 +276,24 @@ func (s *Server) handleGateDecision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Change freezes start and end on time, not with the next evaluation
+	now := time.Now()
+	gateResult := s.scheduler.ApplyFreezes(req.SLOID, state.GateResult, now)
+
 	// Build response
 	burnRates := make(map[string]BurnRateInfo)
 	for window, br := range state.EvalResult.BurnRates {
-		burnRates[window] = BurnRateInfo{
-			BurnRate: br.BurnRate,
-		}
+		burnRates[window] = newBurnRateInfo(br)
 	}
 
-	// Add thresholds from triggered rules
-	for _, rr := range state.GateResult.RuleResults {
-		if rr.Triggered {
+	freshness := make(map[string]string, len(state.EvalResult.Freshness))
+	for window, f := range state.EvalResult.Freshness {
+		freshness[window] = string(f)
+	}
+
+	// Add thresholds from triggered burn rules
+	for _, rr := range gateResult.RuleResults {
+		if rr.Triggered && rr.Kind == policy.RuleKindBurnRate {
 			// Add threshold to corresponding windows
 			// Note: This is simplified - in production you'd match windows to rules more precisely
 			for window := range burnRates {
@@ -270,7 +305,7 @@ func (s *Server) handleGateDecision(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := DecisionResponse{
-		Decision:  string(state.GateResult.Decision),
+		Decision:  string(gateResult.Decision),
 		SLOID:     state.EvalResult.SLOID,
 		Timestamp: state.EvalResult.Timestamp,
 		TTL:       int(state.TTL.Seconds()),
@@ -279,12 +314,190 @@ func (s *Server) handleGateDecision(w http.ResponseWriter, r *http.Request) {
 			ErrorRate:       state.EvalResult.SLI.ErrorRate,
 			BudgetRemaining: state.EvalResult.BudgetRemaining,
 		},
-		Reasons:      state.GateResult.Reasons,
-		BurnRates:    burnRates,
-		IsStale:      state.GateResult.IsStale,
-		HasNoTraffic: state.GateResult.HasNoTraffic,
+		Reasons:         gateResult.Reasons,
+		BurnRates:       burnRates,
+		IsStale:         gateResult.IsStale,
+		Freshness:       freshness,
+		HasNoTraffic:    gateResult.HasNoTraffic,
+		QueryFailed:     gateResult.QueryFailed,
+		Freezes:         gateResult.Freezes,
+		QueryErrors:     state.EvalResult.QueryErrors,
+		Error:           state.EvalResult.Error,
+		Forecast:        newForecastInfo(state.EvalResult.Forecast, state.EvalResult.Timestamp),
+		BudgetEvents:    newBudgetEventsInfo(state.EvalResult.BudgetEvents),
+		Breakdown:       newBreakdownInfo(state.EvalResult.Breakdowns),
+		BreakdownErrors: state.EvalResult.BreakdownErrors,
+	}
+
+	if period := state.EvalResult.Period; period != nil {
+		response.Period = &PeriodInfo{
+			Start:                    period.Start,
+			End:                      period.End,
+			ElapsedFraction:          period.ElapsedFraction,
+			ProjectedBudgetRemaining: period.ProjectedBudgetRemaining,
+		}
+	}
+
+	for _, target := range state.EvalResult.Targets {
+		targetBurnRates := make(map[string]BurnRateInfo)
+		for window, br := range target.Result.BurnRates {
+			targetBurnRates[window] = newBurnRateInfo(br)
+		}
+
+		response.Targets = append(response.Targets, TargetInfo{
+			Name:        target.Name,
+			ThresholdMs: target.ThresholdMs,
+			Objective:   target.Objective,
+			SLI: SLIInfo{
+				Value:           target.Result.SLI.Value,
+				ErrorRate:       target.Result.SLI.ErrorRate,
+				BudgetRemaining: target.Result.BudgetRemaining,
+			},
+			BurnRates: targetBurnRates,
+		})
+	}
+
+	// An active override replaces the decision. Gating must not depend on the audit
+	// storage, so a failed lookup serves the evaluated decision.
+	if overrides, ok := s.scheduler.OverrideStorage(); ok {
+		override, err := overrides.UseOverride(req.SLOID, response.Decision, now)
+		if err != nil {
+			log.Printf("Warning: failed to look up overrides of SLO %s: %v", req.SLOID, err)
+		}
+		if override != nil {
+			info := newOverrideInfo(*override)
+			info.OriginalDecision = response.Decision
+			response.Override = &info
+			response.Decision = override.Decision
+			response.Reasons = append(slices.Clone(response.Reasons), fmt.Sprintf("decision overridden from %s to %s by %s until %s: %s",
+				info.OriginalDecision, override.Decision, override.Approver, override.ExpiresAt.Format(time.RFC3339), override.Reason))
+		}
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// handleOverrides handles POST /v1/overrides (create) and GET /v1/overrides (list active)
+func (s *Server) handleOverrides(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Overrides must be recorded, so they need the audit storage
+	overrides, ok := s.scheduler.OverrideStorage()
+	if !ok {
+		respondError(w, http.StatusServiceUnavailable, "audit storage not configured")
+		return
+	}
+
+	now := time.Now()
+	if r.Method == http.MethodGet {
+		active, err := overrides.ListOverrides(r.URL.Query().Get("sloID"), now)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list overrides: %v", err))
+			return
+		}
+
+		response := OverridesResponse{Overrides: make([]OverrideInfo, len(active))}
+		for i, override := range active {
+			response.Overrides[i] = newOverrideInfo(override)
+		}
+		respondJSON(w, http.StatusOK, response)
+		return
+	}
+
+	var req OverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+
+	switch {
+	case req.SLOID == "":
+		respondError(w, http.StatusBadRequest, "sloID required")
+		return
+	case req.Approver == "" || req.Reason == "":
+		respondError(w, http.StatusBadRequest, "approver and reason required")
+		return
+	case !req.ExpiresAt.After(now):
+		respondError(w, http.StatusBadRequest, "expiresAt must be in the future")
+		return
+	case req.ExpiresAt.After(now.Add(maxOverrideDuration)):
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("expiresAt must be within %s", slo.FormatDuration(maxOverrideDuration)))
+		return
+	}
+	switch policy.Decision(req.Decision) {
+	case policy.DecisionALLOW, policy.DecisionWARN, policy.DecisionBLOCK:
+	default:
+		respondError(w, http.StatusBadRequest, "decision must be ALLOW, WARN or BLOCK")
+		return
+	}
+
+	if !slices.ContainsFunc(s.scheduler.GetSLOs(), func(sloWithFile slo.SLOWithFile) bool {
+		return sloWithFile.SLO.Metadata.ID == req.SLOID
+	}) {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("SLO not found: %s", req.SLOID))
+		return
+	}
+
+	override := storage.Override{
+		SLOID:     req.SLOID,
+		Decision:  req.Decision,
+		Approver:  req.Approver,
+		Reason:    req.Reason,
+		CreatedAt: now,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := overrides.CreateOverride(&override); err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create override: %v", err))
+		return
+	}
+
+	log.Printf("Override %d of SLO %s to %s until %s approved by %s: %s", override.ID, override.SLOID,
+		override.Decision, override.ExpiresAt.Format(time.RFC3339), override.Approver, override.Reason)
+	respondJSON(w, http.StatusCreated, newOverrideInfo(override))
+}
+
+// handleOverrideEvents handles GET /v1/overrides/events
+func (s *Server) handleOverrideEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
 
+	overrides, ok := s.scheduler.OverrideStorage()
+	if !ok {
+		respondError(w, http.StatusServiceUnavailable, "audit storage not configured")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := storage.OverrideEventFilter{SLOID: query.Get("sloID")}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = limit
+		}
+	}
+
+	events, err := overrides.QueryOverrideEvents(filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to query override events: %v", err))
+		return
+	}
+
+	response := OverrideEventsResponse{Events: make([]OverrideEventInfo, len(events)), Total: len(events)}
+	for i, event := range events {
+		response.Events[i] = OverrideEventInfo{
+			ID:               event.ID,
+			OverrideID:       event.OverrideID,
+			SLOID:            event.SLOID,
+			Event:            event.Event,
+			Decision:         event.Decision,
+			OriginalDecision: event.OriginalDecision,
+			Timestamp:        event.Timestamp,
+		}
+	}
 	respondJSON(w, http.StatusOK, response)
 }
 
@@ -311,6 +524,12 @@ func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
 		Decision:    query.Get("decision"),
 	}
 
+	if backfilledStr := query.Get("backfilled"); backfilledStr != "" {
+		if backfilled, err := strconv.ParseBool(backfilledStr); err == nil {
+			filter.Backfilled = &backfilled
+		}
+	}
+
 	if limitStr := query.Get("limit"); limitStr != "" {
 		if limit, err := strconv.Atoi(limitStr); err == nil {
 			filter.Limit = limit
@@ -347,9 +566,7 @@ func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
 	for i, record := range records {
 		burnRates := make(map[string]BurnRateInfo)
 		for window, br := range record.BurnRates {
-			burnRates[window] = BurnRateInfo{
-				BurnRate: br.BurnRate,
-			}
+			burnRates[window] = newBurnRateInfo(br)
 		}
 
 		responseRecords[i] = AuditRecordResponse{
@@ -367,6 +584,10 @@ func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
 			BurnRates:       burnRates,
 			Timestamp:       record.Timestamp,
 			CreatedAt:       record.CreatedAt,
+			Backfilled:      record.Backfilled,
+			QueryFailed:     record.QueryFailed,
+			Forecast:        newForecastInfo(record.Forecast, record.Timestamp),
+			BudgetEvents:    newBudgetEventsInfo(record.BudgetEvents),
 		}
 	}
 
@@ -386,6 +607,208 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
+// handleReload handles POST /v1/admin/reload
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	result, err := s.scheduler.Reload()
+	if err != nil {
+		response := ReloadErrorResponse{Error: fmt.Sprintf("reload failed, previous SLOs kept: %v", err)}
+
+		var loadErr *scheduler.LoadError
+		if errors.As(err, &loadErr) {
+			for _, validationErr := range loadErr.Errors {
+				response.Errors = append(response.Errors, validationErr.Error())
+			}
+		}
+
+		respondJSON(w, http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, ReloadResponse{
+		Added:     nonNil(result.Added),
+		Removed:   nonNil(result.Removed),
+		Changed:   nonNil(result.Changed),
+		Unchanged: result.Unchanged,
+	})
+}
+
+// handleQueryCache handles GET /v1/admin/query-cache
+func (s *Server) handleQueryCache(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	stats, ok := s.scheduler.QueryStats()
+	if !ok {
+		respondError(w, http.StatusNotFound, "metrics adapter does not cache queries")
+		return
+	}
+
+	response := QueryCacheResponse{
+		Hits:    stats.Hits,
+		Misses:  stats.Misses,
+		Entries: stats.Entries,
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		response.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// handleFreezes lists the change freezes active now or starting within the horizon
+// (default 7d), optionally only those applying to a service and environment
+func (s *Server) handleFreezes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	horizon := defaultFreezeHorizon
+	if horizonStr := query.Get("horizon"); horizonStr != "" {
+		parsed, err := slo.ParseDuration(horizonStr)
+		if err != nil || parsed <= 0 || parsed > maxFreezeHorizon {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid horizon %q: must be a duration up to %s", horizonStr, slo.FormatDuration(maxFreezeHorizon)))
+			return
+		}
+		horizon = parsed
+	}
+
+	now := time.Now()
+	response := FreezesResponse{
+		Timestamp: now,
+		Active:    []FreezeInfo{},
+		Upcoming:  []FreezeInfo{},
+	}
+
+	calendar := s.scheduler.FreezeCalendar()
+	if calendar == nil {
+		respondJSON(w, http.StatusOK, response)
+		return
+	}
+
+	service, environment := query.Get("service"), query.Get("environment")
+	for _, window := range calendar.Windows(now, horizon) {
+		freeze := window.Freeze
+		if (service != "" && len(freeze.Services) > 0 && !slices.Contains(freeze.Services, service)) ||
+			(environment != "" && len(freeze.Environments) > 0 && !slices.Contains(freeze.Environments, environment)) {
+			continue
+		}
+
+		info := FreezeInfo{
+			Name:         freeze.Name,
+			Reason:       freeze.Reason,
+			Action:       freeze.Action,
+			Start:        window.Start,
+			End:          window.End,
+			Schedule:     freeze.Schedule,
+			Services:     freeze.Services,
+			Environments: freeze.Environments,
+		}
+		if window.Active(now) {
+			response.Active = append(response.Active, info)
+		} else {
+			response.Upcoming = append(response.Upcoming, info)
+		}
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// nonNil returns an empty slice for nil so it encodes as [] rather than null
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
+
+// newBurnRateInfo converts an evaluated burn rate for the API
+func newBurnRateInfo(br eval.BurnRateResult) BurnRateInfo {
+	return BurnRateInfo{
+		BurnRate:           br.BurnRate,
+		LowerBound:         br.BurnRateLowerBound,
+		Events:             br.Events,
+		InsufficientEvents: br.InsufficientEvents,
+	}
+}
+
+// newForecastInfo converts a forecast made at the given time for the API
+func newForecastInfo(forecast *eval.Forecast, at time.Time) *ForecastInfo {
+	if forecast == nil {
+		return nil
+	}
+
+	info := &ForecastInfo{
+		Basis:                    forecast.Basis,
+		Window:                   forecast.Window,
+		BurnRate:                 forecast.BurnRate,
+		ProjectedBudgetRemaining: forecast.ProjectedBudgetRemaining,
+	}
+	if ttl := forecast.TimeToExhaustion; ttl != nil {
+		seconds := ttl.Seconds()
+		exhaustion := at.Add(*ttl)
+		info.TimeToExhaustionSeconds = &seconds
+		info.ExhaustionTime = &exhaustion
+	}
+	return info
+}
+
+// newBudgetEventsInfo converts a budget in bad events for the API
+func newBudgetEventsInfo(budget *eval.BudgetEvents) *BudgetEventsInfo {
+	if budget == nil {
+		return nil
+	}
+	return &BudgetEventsInfo{
+		Allowed:   budget.Allowed,
+		Consumed:  budget.Consumed,
+		Remaining: budget.Remaining,
+	}
+}
+
+// newBreakdownInfo converts the top contributors of each window for the API
+func newBreakdownInfo(breakdowns map[string][]eval.Contributor) map[string][]ContributorInfo {
+	if len(breakdowns) == 0 {
+		return nil
+	}
+
+	info := make(map[string][]ContributorInfo, len(breakdowns))
+	for window, contributors := range breakdowns {
+		infos := make([]ContributorInfo, len(contributors))
+		for i, c := range contributors {
+			infos[i] = ContributorInfo{
+				Labels:    c.Labels,
+				Good:      c.Good,
+				Total:     c.Total,
+				BadEvents: c.BadEvents,
+				Share:     c.Share,
+			}
+		}
+		info[window] = infos
+	}
+	return info
+}
+
+// newOverrideInfo converts a decision override for the API
+func newOverrideInfo(override storage.Override) OverrideInfo {
+	return OverrideInfo{
+		ID:        override.ID,
+		SLOID:     override.SLOID,
+		Decision:  override.Decision,
+		Approver:  override.Approver,
+		Reason:    override.Reason,
+		CreatedAt: override.CreatedAt,
+		ExpiresAt: override.ExpiresAt,
+	}
+}
+
 func respondError(w http.ResponseWriter, status int, message string) {
 	respondJSON(w, status, ErrorResponse{Error: message})
 }
@@ -3,8 +3,10 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -13,6 +15,8 @@ import (
 	"github.com/samijaber1/aegis-slo/internal/policy"
 	"github.com/samijaber1/aegis-slo/internal/scheduler"
 	"github.com/samijaber1/aegis-slo/internal/slo"
+	"github.com/samijaber1/aegis-slo/internal/storage"
+	"github.com/samijaber1/aegis-slo/internal/storage/sqlite"
 )
 
 func setupTestServer(t *testing.T) (*Server, *scheduler.Scheduler) {
@@ -222,6 +226,8 @@ func TestMethodNotAllowed(t *testing.T) {
 		{"/readyz", "POST"},
 		{"/v1/slo", "POST"},
 		{"/v1/gate/decision", "GET"},
+		{"/v1/admin/reload", "GET"},
+		{"/v1/admin/query-cache", "POST"},
 	}
 
 	for _, tt := range tests {
@@ -234,6 +240,8 @@ func TestMethodNotAllowed(t *testing.T) {
 			mux.HandleFunc("/readyz", server.handleReady)
 			mux.HandleFunc("/v1/slo", server.handleSLOList)
 			mux.HandleFunc("/v1/gate/decision", server.handleGateDecision)
+			mux.HandleFunc("/v1/admin/reload", server.handleReload)
+			mux.HandleFunc("/v1/admin/query-cache", server.handleQueryCache)
 
 			mux.ServeHTTP(w, req)
 
@@ -243,3 +251,255 @@ func TestMethodNotAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestReloadEndpoint(t *testing.T) {
+	server, sched := setupTestServer(t)
+
+	// Schema path is relative to the working directory; an unreadable schema is rejected
+	req := httptest.NewRequest("POST", "/v1/admin/reload", nil)
+	w := httptest.NewRecorder()
+	server.handleReload(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d", w.Code)
+	}
+
+	sched.SetSchemaPath("../../schemas/slo_v1.json")
+
+	w = httptest.NewRecorder()
+	server.handleReload(w, httptest.NewRequest("POST", "/v1/admin/reload", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp ReloadResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(resp.Added) == 0 || len(resp.Added) != len(sched.GetSLOs()) {
+		t.Errorf("expected every fixture SLO to be added, got %v", resp.Added)
+	}
+	if resp.Removed == nil || resp.Changed == nil {
+		t.Error("expected empty lists rather than null")
+	}
+}
+
+func TestQueryCacheEndpoint_NoCache(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	// The synthetic adapter answers from memory and keeps no query cache
+	w := httptest.NewRecorder()
+	server.handleQueryCache(w, httptest.NewRequest("GET", "/v1/admin/query-cache", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestGateDecisionEndpoint_FreezeAtRequestTime(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name            string
+		start, end      time.Time
+		expectedDec     string
+		expectedFreezes int
+	}{
+		{name: "freeze started after the evaluation", start: now.Add(-time.Minute), end: now.Add(time.Hour), expectedDec: "BLOCK", expectedFreezes: 1},
+		{name: "freeze ended after the evaluation", start: now.Add(-time.Hour), end: now.Add(-time.Minute), expectedDec: "ALLOW"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar, err := policy.ParseFreezeCalendar([]byte(fmt.Sprintf(`
+freezes:
+  - name: incident
+    start: %s
+    end: %s
+`, tt.start.Format(time.RFC3339), tt.end.Format(time.RFC3339))))
+			if err != nil {
+				t.Fatalf("failed to parse freeze calendar: %v", err)
+			}
+
+			policyEngine := policy.NewEngine()
+			policyEngine.SetFreezeCalendar(calendar)
+			sched := scheduler.NewScheduler(eval.NewEvaluator(synthetic.NewAdapter()), policyEngine, "../../fixtures/slo/valid")
+
+			sloSpec := &slo.SLO{Metadata: slo.Metadata{ID: "test-slo", Service: "checkout"}, Spec: slo.Spec{Environment: "prod"}}
+			sched.SetSLOsForTest([]slo.SLOWithFile{{SLO: sloSpec}})
+
+			// Evaluated five minutes ago, outside both freezes' edges
+			evalResult := &eval.EvaluationResult{SLOID: "test-slo", Timestamp: now.Add(-5 * time.Minute)}
+			sched.GetCache().Set("test-slo", &scheduler.EvaluationState{
+				EvalResult: evalResult,
+				GateResult: policyEngine.EvaluateHealth(sloSpec, evalResult),
+				UpdatedAt:  evalResult.Timestamp,
+				TTL:        time.Hour,
+			})
+
+			server := NewServer(sched, ":0")
+			body, _ := json.Marshal(DecisionRequest{SLOID: "test-slo"})
+			w := httptest.NewRecorder()
+			server.handleGateDecision(w, httptest.NewRequest("POST", "/v1/gate/decision", bytes.NewReader(body)))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var resp DecisionResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Decision != tt.expectedDec || len(resp.Freezes) != tt.expectedFreezes {
+				t.Errorf("expected %s with %d freezes, got %s with %v (reasons: %v)", tt.expectedDec, tt.expectedFreezes, resp.Decision, resp.Freezes, resp.Reasons)
+			}
+		})
+	}
+}
+
+func TestFreezesEndpoint(t *testing.T) {
+	now := time.Now().UTC()
+	calendar, err := policy.ParseFreezeCalendar([]byte(fmt.Sprintf(`
+freezes:
+  - name: incident
+    start: %s
+    end: %s
+  - name: payments-migration
+    start: %s
+    end: %s
+    services: [payment]
+`, now.Add(-time.Hour).Format(time.RFC3339), now.Add(time.Hour).Format(time.RFC3339),
+		now.Add(48*time.Hour).Format(time.RFC3339), now.Add(50*time.Hour).Format(time.RFC3339))))
+	if err != nil {
+		t.Fatalf("failed to parse freeze calendar: %v", err)
+	}
+
+	policyEngine := policy.NewEngine()
+	policyEngine.SetFreezeCalendar(calendar)
+	sched := scheduler.NewScheduler(eval.NewEvaluator(synthetic.NewAdapter()), policyEngine, "../../fixtures/slo/valid")
+	server := NewServer(sched, ":0")
+
+	tests := []struct {
+		name             string
+		query            string
+		expectedStatus   int
+		expectedActive   int
+		expectedUpcoming int
+	}{
+		{name: "default horizon", query: "", expectedStatus: http.StatusOK, expectedActive: 1, expectedUpcoming: 1},
+		{name: "short horizon", query: "?horizon=1d", expectedStatus: http.StatusOK, expectedActive: 1},
+		{name: "other service", query: "?service=checkout", expectedStatus: http.StatusOK, expectedActive: 1},
+		{name: "invalid horizon", query: "?horizon=forever", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.handleFreezes(w, httptest.NewRequest("GET", "/v1/freezes"+tt.query, nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var resp FreezesResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Active) != tt.expectedActive || len(resp.Upcoming) != tt.expectedUpcoming {
+				t.Errorf("expected %d active and %d upcoming freezes, got %+v", tt.expectedActive, tt.expectedUpcoming, resp)
+			}
+		})
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	server, sched := setupTestServer(t)
+
+	// Without audit storage overrides are unavailable
+	w := httptest.NewRecorder()
+	server.handleOverrides(w, httptest.NewRequest("GET", "/v1/overrides", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 without audit storage, got %d", w.Code)
+	}
+
+	store, err := sqlite.NewStore(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	sched.SetAuditStorage(store)
+	sched.SetSLOsForTest([]slo.SLOWithFile{
+		{SLO: &slo.SLO{Metadata: slo.Metadata{ID: "test-slo", Service: "test-service"}}, File: "test.yaml"},
+	})
+
+	expiresAt := time.Now().Add(time.Hour)
+	valid := OverrideRequest{SLOID: "test-slo", Decision: "BLOCK", ExpiresAt: expiresAt, Approver: "alice", Reason: "INC-42 rollback only"}
+
+	tests := []struct {
+		name           string
+		modify         func(req *OverrideRequest)
+		expectedStatus int
+	}{
+		{name: "missing approver", modify: func(req *OverrideRequest) { req.Approver = "" }, expectedStatus: http.StatusBadRequest},
+		{name: "expired", modify: func(req *OverrideRequest) { req.ExpiresAt = time.Now().Add(-time.Minute) }, expectedStatus: http.StatusBadRequest},
+		{name: "too long", modify: func(req *OverrideRequest) { req.ExpiresAt = time.Now().Add(8 * 24 * time.Hour) }, expectedStatus: http.StatusBadRequest},
+		{name: "invalid decision", modify: func(req *OverrideRequest) { req.Decision = "MAYBE" }, expectedStatus: http.StatusBadRequest},
+		{name: "unknown SLO", modify: func(req *OverrideRequest) { req.SLOID = "nonexistent" }, expectedStatus: http.StatusNotFound},
+		{name: "created", modify: func(req *OverrideRequest) {}, expectedStatus: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			body, _ := json.Marshal(req)
+
+			w := httptest.NewRecorder()
+			server.handleOverrides(w, httptest.NewRequest("POST", "/v1/overrides", bytes.NewReader(body)))
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// The gate decision reports the override in place of the evaluated decision
+	body, _ := json.Marshal(DecisionRequest{SLOID: "test-slo"})
+	w = httptest.NewRecorder()
+	server.handleGateDecision(w, httptest.NewRequest("POST", "/v1/gate/decision", bytes.NewReader(body)))
+
+	var decision DecisionResponse
+	if err := json.NewDecoder(w.Body).Decode(&decision); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if decision.Decision != "BLOCK" || decision.Override == nil || decision.Override.OriginalDecision != "ALLOW" {
+		t.Errorf("expected BLOCK overriding ALLOW, got %s (override %+v)", decision.Decision, decision.Override)
+	}
+
+	w = httptest.NewRecorder()
+	server.handleOverrideEvents(w, httptest.NewRequest("GET", "/v1/overrides/events?sloID=test-slo", nil))
+
+	var events OverrideEventsResponse
+	if err := json.NewDecoder(w.Body).Decode(&events); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if events.Total != 2 || events.Events[0].Event != storage.OverrideUsed || events.Events[1].Event != storage.OverrideCreated {
+		t.Errorf("expected used and created events, got %+v", events.Events)
+	}
+
+	// Without a working audit database the evaluated decision is served
+	store.Close()
+	w = httptest.NewRecorder()
+	server.handleGateDecision(w, httptest.NewRequest("POST", "/v1/gate/decision", bytes.NewReader(body)))
+
+	decision = DecisionResponse{}
+	if err := json.NewDecoder(w.Body).Decode(&decision); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || decision.Decision != "ALLOW" || decision.Override != nil {
+		t.Errorf("expected status 200 with ALLOW, got %d with %s (override %+v)", w.Code, decision.Decision, decision.Override)
+	}
+}
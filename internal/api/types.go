@@ -20,7 +20,63 @@ type DecisionResponse struct {
 	Reasons      []string                `json:"reasons"`
 	BurnRates    map[string]BurnRateInfo `json:"burnRates"`
 	IsStale      bool                    `json:"isStale"`
+	Freshness    map[string]string       `json:"freshness"` // fresh, stale or unknown, keyed by window
 	HasNoTraffic bool                    `json:"hasNoTraffic"`
+	QueryFailed  bool                    `json:"queryFailed"`
+	Freezes      []string                `json:"freezes,omitempty"`     // change freezes in effect
+	Override     *OverrideInfo           `json:"override,omitempty"`    // active override that replaced the decision
+	QueryErrors  map[string]string       `json:"queryErrors,omitempty"` // failed queries keyed by window
+	Error        string                  `json:"error,omitempty"`       // the evaluation failed outright
+	Targets      []TargetInfo            `json:"targets,omitempty"`
+	Period       *PeriodInfo             `json:"period,omitempty"`
+	Forecast     *ForecastInfo           `json:"forecast,omitempty"`
+	BudgetEvents *BudgetEventsInfo       `json:"budgetEvents,omitempty"`
+	// Top contributors to bad events, keyed by burning window
+	Breakdown       map[string][]ContributorInfo `json:"breakdown,omitempty"`
+	BreakdownErrors map[string]string            `json:"breakdownErrors,omitempty"` // failed breakdowns keyed by window
+}
+
+// ContributorInfo is a group of series and its share of a window's bad events
+type ContributorInfo struct {
+	Labels    map[string]string `json:"labels"`
+	Good      float64           `json:"good"`
+	Total     float64           `json:"total"`
+	BadEvents float64           `json:"badEvents"`
+	Share     float64           `json:"share"`
+}
+
+// BudgetEventsInfo is the error budget of the compliance window in bad events
+type BudgetEventsInfo struct {
+	Allowed   float64 `json:"allowed"`
+	Consumed  float64 `json:"consumed"`
+	Remaining float64 `json:"remaining"`
+}
+
+// ForecastInfo projects error budget consumption at the current pace
+type ForecastInfo struct {
+	Basis                    string     `json:"basis"` // burn_rate or history
+	Window                   string     `json:"window"`
+	BurnRate                 float64    `json:"burnRate"`
+	TimeToExhaustionSeconds  *float64   `json:"timeToExhaustionSeconds,omitempty"` // absent when the budget is not being consumed
+	ExhaustionTime           *time.Time `json:"exhaustionTime,omitempty"`
+	ProjectedBudgetRemaining float64    `json:"projectedBudgetRemaining"`
+}
+
+// PeriodInfo describes the current period of a calendar-aligned compliance window
+type PeriodInfo struct {
+	Start                    time.Time `json:"start"`
+	End                      time.Time `json:"end"`
+	ElapsedFraction          float64   `json:"elapsedFraction"`
+	ProjectedBudgetRemaining float64   `json:"projectedBudgetRemaining"`
+}
+
+// TargetInfo contains the evaluation of a single latency distribution target
+type TargetInfo struct {
+	Name        string                  `json:"name"`
+	ThresholdMs int                     `json:"thresholdMs"`
+	Objective   float64                 `json:"objective"`
+	SLI         SLIInfo                 `json:"sli"`
+	BurnRates   map[string]BurnRateInfo `json:"burnRates"`
 }
 
 // SLIInfo contains SLI metrics
@@ -32,8 +88,11 @@ type SLIInfo struct {
 
 // BurnRateInfo contains burn rate information for a window
 type BurnRateInfo struct {
-	BurnRate  float64 `json:"burnRate"`
-	Threshold float64 `json:"threshold,omitempty"`
+	BurnRate           float64  `json:"burnRate"`
+	LowerBound         *float64 `json:"lowerBound,omitempty"` // with gating.confidence
+	Threshold          float64  `json:"threshold,omitempty"`
+	Events             float64  `json:"events"`
+	InsufficientEvents bool     `json:"insufficientEvents,omitempty"`
 }
 
 // SLOListResponse represents a list of SLOs
@@ -44,9 +103,11 @@ type SLOListResponse struct {
 // SLOSummary contains summary information about an SLO
 type SLOSummary struct {
 	ID          string  `json:"id"`
+	Kind        string  `json:"kind"`
 	Service     string  `json:"service"`
 	Environment string  `json:"environment"`
 	Objective   float64 `json:"objective"`
+	Parent      string  `json:"parent,omitempty"` // expandBy SLO this child was generated from
 }
 
 // StateResponse represents the evaluation state for a service/environment
@@ -75,6 +136,91 @@ type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// ReloadResponse represents the result of POST /v1/admin/reload
+type ReloadResponse struct {
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+	Changed   []string `json:"changed"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// ReloadErrorResponse represents a rejected reload; the previous SLOs stay loaded
+type ReloadErrorResponse struct {
+	Error  string   `json:"error"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// QueryCacheResponse represents the query cache counters of GET /v1/admin/query-cache
+type QueryCacheResponse struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	Entries  int     `json:"entries"`
+	HitRatio float64 `json:"hitRatio"`
+}
+
+// FreezesResponse lists the change freezes of GET /v1/freezes
+type FreezesResponse struct {
+	Timestamp time.Time    `json:"timestamp"`
+	Active    []FreezeInfo `json:"active"`
+	Upcoming  []FreezeInfo `json:"upcoming"` // starting within the requested horizon
+}
+
+// FreezeInfo is one occurrence of a change freeze
+type FreezeInfo struct {
+	Name         string    `json:"name"`
+	Reason       string    `json:"reason,omitempty"`
+	Action       string    `json:"action"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	Schedule     string    `json:"schedule,omitempty"` // recurring freezes only
+	Services     []string  `json:"services,omitempty"`
+	Environments []string  `json:"environments,omitempty"`
+}
+
+// OverrideRequest represents a POST /v1/overrides request
+type OverrideRequest struct {
+	SLOID     string    `json:"sloID"`
+	Decision  string    `json:"decision"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Approver  string    `json:"approver"`
+	Reason    string    `json:"reason"`
+}
+
+// OverrideInfo describes a decision override
+type OverrideInfo struct {
+	ID        int64     `json:"id"`
+	SLOID     string    `json:"sloID"`
+	Decision  string    `json:"decision"`
+	Approver  string    `json:"approver"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Gate decision responses only: the decision the override replaced
+	OriginalDecision string `json:"originalDecision,omitempty"`
+}
+
+// OverridesResponse lists the active overrides of GET /v1/overrides
+type OverridesResponse struct {
+	Overrides []OverrideInfo `json:"overrides"`
+}
+
+// OverrideEventInfo is a recorded creation, use or expiry of an override
+type OverrideEventInfo struct {
+	ID               int64     `json:"id"`
+	OverrideID       int64     `json:"overrideID"`
+	SLOID            string    `json:"sloID"`
+	Event            string    `json:"event"` // created, used or expired
+	Decision         string    `json:"decision"`
+	OriginalDecision string    `json:"originalDecision,omitempty"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// OverrideEventsResponse lists override events of GET /v1/overrides/events
+type OverrideEventsResponse struct {
+	Events []OverrideEventInfo `json:"events"`
+	Total  int                 `json:"total"`
+}
+
 // AuditQueryParams represents query parameters for audit endpoint
 type AuditQueryParams struct {
 	SLOID       string
@@ -109,4 +255,8 @@ type AuditRecordResponse struct {
 	BurnRates       map[string]BurnRateInfo `json:"burnRates"`
 	Timestamp       time.Time               `json:"timestamp"`
 	CreatedAt       time.Time               `json:"createdAt"`
+	Backfilled      bool                    `json:"backfilled"`
+	QueryFailed     bool                    `json:"queryFailed"`
+	Forecast        *ForecastInfo           `json:"forecast,omitempty"`
+	BudgetEvents    *BudgetEventsInfo       `json:"budgetEvents,omitempty"`
 }
@@ -13,15 +13,47 @@ type Config struct {
 
 	// SLO settings
 	SLODirectory string
+	SLOSources   []string // additional sources, see slo.ParseSource
 
 	// Metrics adapter settings
 	AdapterType     string // "prometheus" or "synthetic"
 	PrometheusURL   string
 	SyntheticFixDir string
 
+	// How long Prometheus query results are shared across SLOs; 0 only deduplicates in-flight queries
+	QueryCacheFreshness time.Duration
+
+	// Resolution of the Prometheus count_over_time query reporting the sample count of
+	// each window's total series; 0 disables it
+	SampleCountStep time.Duration
+
+	// Query strategy: "instant" (one query per window) or "range" (one range query
+	// per series at RangeStep, windows derived locally)
+	QueryStrategy string
+	RangeStep     time.Duration
+
+	// Decision for evaluations whose queries failed: ALLOW, WARN or BLOCK
+	QueryErrorDecision string
+
+	// YAML file of change freezes applied to every gate decision; empty disables freezes
+	FreezeCalendarPath string
+
+	// Interval between label value discoveries for expandBy SLOs
+	DiscoveryInterval time.Duration
+
+	// Interval between polls of the SLO sources for changes; 0 disables watching
+	WatchInterval time.Duration
+
 	// Storage settings
 	DatabasePath string
 
+	// Backfill mode: evaluate BackfillSLO every BackfillStep from BackfillStart to
+	// BackfillEnd, store the results as backfilled audit records and exit
+	BackfillSLO   string
+	BackfillStart time.Time
+	BackfillEnd   time.Time
+	BackfillStep  time.Duration
+
 	// Operational settings
 	GracefulShutdownTimeout time.Duration
 }
@@ -32,8 +64,8 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid port: %d", c.Port)
 	}
 
-	if c.SLODirectory == "" {
-		return fmt.Errorf("SLO directory is required")
+	if c.SLODirectory == "" && len(c.SLOSources) == 0 {
+		return fmt.Errorf("SLO directory or at least one SLO source is required")
 	}
 
 	if c.AdapterType != "prometheus" && c.AdapterType != "synthetic" {
@@ -44,6 +76,48 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("Prometheus URL required when adapter type is 'prometheus'")
 	}
 
+	switch c.QueryErrorDecision {
+	case "ALLOW", "WARN", "BLOCK":
+	default:
+		return fmt.Errorf("query error decision must be ALLOW, WARN or BLOCK")
+	}
+
+	if c.QueryStrategy != "instant" && c.QueryStrategy != "range" {
+		return fmt.Errorf("query strategy must be 'instant' or 'range'")
+	}
+
+	if c.QueryStrategy == "range" && c.RangeStep <= 0 {
+		return fmt.Errorf("range step must be positive")
+	}
+
+	if c.QueryCacheFreshness < 0 {
+		return fmt.Errorf("query cache freshness must not be negative")
+	}
+
+	if c.SampleCountStep < 0 {
+		return fmt.Errorf("sample count step must not be negative")
+	}
+
+	if c.DiscoveryInterval <= 0 {
+		return fmt.Errorf("discovery interval must be positive")
+	}
+
+	if c.WatchInterval < 0 {
+		return fmt.Errorf("watch interval must not be negative")
+	}
+
+	if c.BackfillSLO != "" {
+		if c.DatabasePath == "" {
+			return fmt.Errorf("backfill requires a database")
+		}
+		if c.BackfillStart.IsZero() {
+			return fmt.Errorf("backfill requires a start time")
+		}
+		if c.BackfillStep <= 0 {
+			return fmt.Errorf("backfill step must be positive")
+		}
+	}
+
 	return nil
 }
 
@@ -54,6 +128,13 @@ func DefaultConfig() Config {
 		Host:                    "0.0.0.0",
 		AdapterType:             "synthetic",
 		DatabasePath:            "aegis.db",
+		QueryCacheFreshness:     30 * time.Second,
+		QueryStrategy:           "instant",
+		QueryErrorDecision:      "WARN",
+		RangeStep:               5 * time.Minute,
+		DiscoveryInterval:       5 * time.Minute,
+		WatchInterval:           30 * time.Second,
+		BackfillStep:            time.Hour,
 		GracefulShutdownTimeout: 30 * time.Second,
 	}
 }
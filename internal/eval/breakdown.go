@@ -0,0 +1,193 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/slo"
+)
+
+// maxContributors is the number of top contributors kept per burning window
+const maxContributors = 5
+
+// attachBreakdowns ranks the contributors to bad events in each burning window of an SLO
+// that declares sli.breakdownBy. Breakdown queries run concurrently; a failed one is
+// reported in BreakdownErrors and does not affect the evaluation. Only ctx's error is
+// returned.
+func (e *Evaluator) attachBreakdowns(ctx context.Context, sloSpec *slo.SLO, result *EvaluationResult, at time.Time) error {
+	labels := sloSpec.Spec.SLI.BreakdownBy
+	if len(labels) == 0 || sloSpec.Spec.SLI.Type == "time_slice" {
+		return nil
+	}
+
+	windows := burningWindows(sloSpec, result)
+	if len(windows) == 0 {
+		return nil
+	}
+
+	breakdownAdapter, ok := e.adapter.(BreakdownAdapter)
+	if !ok {
+		result.BreakdownErrors = make(map[string]string, len(windows))
+		for _, window := range windows {
+			result.BreakdownErrors[window] = "metrics adapter does not support breakdowns"
+		}
+		return nil
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, window := range windows {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			groups, err := breakdownAdapter.QueryBreakdown(ctx, RatioQuery{
+				Good:   sloSpec.Spec.SLI.Good.PrometheusQuery,
+				Total:  sloSpec.Spec.SLI.Total.PrometheusQuery,
+				Window: window,
+				Time:   at,
+			}, labels)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if result.BreakdownErrors == nil {
+					result.BreakdownErrors = make(map[string]string)
+				}
+				result.BreakdownErrors[window] = fmt.Sprintf("breakdown by %s: %v", strings.Join(labels, ", "), err)
+				return
+			}
+			if result.Breakdowns == nil {
+				result.Breakdowns = make(map[string][]Contributor)
+			}
+			result.Breakdowns[window] = RankContributors(groups, maxContributors)
+		}()
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// burningWindows returns the windows of the burn rules that trigger, sorted: both windows
+// have enough events and reach the rule's threshold
+func burningWindows(sloSpec *slo.SLO, result *EvaluationResult) []string {
+	burning := make(map[string]bool)
+	for _, rule := range sloSpec.Spec.BurnPolicy.Rules {
+		short, shortOK := result.BurnRates[rule.ShortWindow]
+		long, longOK := result.BurnRates[rule.LongWindow]
+		if !shortOK || !longOK || short.InsufficientEvents || long.InsufficientEvents {
+			continue
+		}
+		if short.GatedBurnRate() >= rule.Threshold && long.GatedBurnRate() >= rule.Threshold {
+			burning[rule.ShortWindow] = true
+			burning[rule.LongWindow] = true
+		}
+	}
+
+	windows := make([]string, 0, len(burning))
+	for window := range burning {
+		windows = append(windows, window)
+	}
+	sort.Strings(windows)
+	return windows
+}
+
+// RankContributors orders groups by bad events, largest first, and keeps the top limit
+// groups that have any. Share is relative to the bad events of all groups.
+func RankContributors(groups []GroupRatio, limit int) []Contributor {
+	var totalBad float64
+	contributors := make([]Contributor, 0, len(groups))
+	for _, group := range groups {
+		bad := math.Max(0, group.Total-group.Good)
+		if bad == 0 {
+			continue
+		}
+		totalBad += bad
+		contributors = append(contributors, Contributor{
+			Labels:    group.Labels,
+			Good:      group.Good,
+			Total:     group.Total,
+			BadEvents: bad,
+		})
+	}
+
+	sort.SliceStable(contributors, func(i, j int) bool {
+		if contributors[i].BadEvents != contributors[j].BadEvents {
+			return contributors[i].BadEvents > contributors[j].BadEvents
+		}
+		return FormatLabels(contributors[i].Labels) < FormatLabels(contributors[j].Labels)
+	})
+
+	if len(contributors) > limit {
+		contributors = contributors[:limit]
+	}
+	for i := range contributors {
+		contributors[i].Share = contributors[i].BadEvents / totalBad
+	}
+	return contributors
+}
+
+// RatioGroups sums good and total series per group of values of a set of labels, for
+// adapters implementing BreakdownAdapter. Groups keep the order they were first seen in.
+type RatioGroups struct {
+	labels []string
+	groups map[string]*GroupRatio
+	order  []string
+}
+
+// NewRatioGroups creates an empty set of groups over labels
+func NewRatioGroups(labels []string) *RatioGroups {
+	return &RatioGroups{labels: labels, groups: make(map[string]*GroupRatio)}
+}
+
+// AddGood adds a good series value to the group of the series' labels
+func (g *RatioGroups) AddGood(seriesLabels map[string]string, value float64) {
+	g.group(seriesLabels).Good += value
+}
+
+// AddTotal adds a total series value to the group of the series' labels
+func (g *RatioGroups) AddTotal(seriesLabels map[string]string, value float64) {
+	g.group(seriesLabels).Total += value
+}
+
+// Ratios returns the groups
+func (g *RatioGroups) Ratios() []GroupRatio {
+	ratios := make([]GroupRatio, len(g.order))
+	for i, key := range g.order {
+		ratios[i] = *g.groups[key]
+	}
+	return ratios
+}
+
+func (g *RatioGroups) group(seriesLabels map[string]string) *GroupRatio {
+	values := make(map[string]string, len(g.labels))
+	for _, label := range g.labels {
+		values[label] = seriesLabels[label]
+	}
+
+	key := FormatLabels(values)
+	if _, ok := g.groups[key]; !ok {
+		g.groups[key] = &GroupRatio{Labels: values}
+		g.order = append(g.order, key)
+	}
+	return g.groups[key]
+}
+
+// FormatLabels renders label values as name="value" pairs sorted by name
+func FormatLabels(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s=%q", name, labels[name])
+	}
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,160 @@
+package eval
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/slo"
+)
+
+func TestRankContributors(t *testing.T) {
+	route := func(name string, good, total float64) GroupRatio {
+		return GroupRatio{Labels: map[string]string{"route": name}, Good: good, Total: total}
+	}
+
+	tests := []struct {
+		name           string
+		groups         []GroupRatio
+		limit          int
+		expectedRoutes []string
+		expectedShares []float64
+	}{
+		{
+			name:           "ordered by bad events",
+			groups:         []GroupRatio{route("/a", 90, 100), route("/b", 40, 100), route("/c", 100, 100)},
+			limit:          5,
+			expectedRoutes: []string{"/b", "/a"},
+			expectedShares: []float64{60.0 / 70.0, 10.0 / 70.0},
+		},
+		{
+			name:           "ties ordered by labels",
+			groups:         []GroupRatio{route("/b", 0, 10), route("/a", 0, 10)},
+			limit:          5,
+			expectedRoutes: []string{"/a", "/b"},
+			expectedShares: []float64{0.5, 0.5},
+		},
+		{
+			name:           "share counts groups beyond the limit",
+			groups:         []GroupRatio{route("/a", 0, 30), route("/b", 0, 20), route("/c", 0, 50)},
+			limit:          2,
+			expectedRoutes: []string{"/c", "/a"},
+			expectedShares: []float64{0.5, 0.3},
+		},
+		{
+			name:   "no bad events",
+			groups: []GroupRatio{route("/a", 100, 100)},
+			limit:  5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contributors := RankContributors(tt.groups, tt.limit)
+			if len(contributors) != len(tt.expectedRoutes) {
+				t.Fatalf("expected %d contributors, got %+v", len(tt.expectedRoutes), contributors)
+			}
+			for i, c := range contributors {
+				if c.Labels["route"] != tt.expectedRoutes[i] {
+					t.Errorf("contributor %d: expected route %s, got %s", i, tt.expectedRoutes[i], c.Labels["route"])
+				}
+				if math.Abs(c.Share-tt.expectedShares[i]) > 0.0001 {
+					t.Errorf("contributor %d: expected share %.4f, got %.4f", i, tt.expectedShares[i], c.Share)
+				}
+			}
+		})
+	}
+}
+
+func TestRatioGroups(t *testing.T) {
+	groups := NewRatioGroups([]string{"route"})
+	groups.AddTotal(map[string]string{"route": "/a", "code": "200"}, 60)
+	groups.AddTotal(map[string]string{"route": "/a", "code": "500"}, 40)
+	groups.AddTotal(map[string]string{"route": "/b"}, 10)
+	groups.AddGood(map[string]string{"route": "/a", "code": "200"}, 60)
+	groups.AddGood(map[string]string{}, 5)
+
+	expected := []GroupRatio{
+		{Labels: map[string]string{"route": "/a"}, Good: 60, Total: 100},
+		{Labels: map[string]string{"route": "/b"}, Total: 10},
+		{Labels: map[string]string{"route": ""}, Good: 5},
+	}
+	ratios := groups.Ratios()
+	if len(ratios) != len(expected) {
+		t.Fatalf("expected %d groups, got %+v", len(expected), ratios)
+	}
+	for i, ratio := range ratios {
+		if FormatLabels(ratio.Labels) != FormatLabels(expected[i].Labels) || ratio.Good != expected[i].Good || ratio.Total != expected[i].Total {
+			t.Errorf("group %d: expected %+v, got %+v", i, expected[i], ratio)
+		}
+	}
+}
+
+// breakdownAdapter answers every window with a burn rate of 50 against a 0.99 objective
+// and breaks bad events down by route
+type breakdownAdapter struct{}
+
+func (b *breakdownAdapter) QueryRatio(ctx context.Context, query RatioQuery) (RatioResult, error) {
+	return RatioResult{Window: query.Window, Good: SeriesValue{Value: 500}, Total: SeriesValue{Value: 1000}}, nil
+}
+
+func (b *breakdownAdapter) QueryBreakdown(ctx context.Context, query RatioQuery, labels []string) ([]GroupRatio, error) {
+	return []GroupRatio{
+		{Labels: map[string]string{"route": "/checkout"}, Good: 100, Total: 500},
+		{Labels: map[string]string{"route": "/search"}, Good: 400, Total: 500},
+	}, nil
+}
+
+func TestEvaluator_Breakdowns(t *testing.T) {
+	sloSpec := parallelTestSLO()
+	sloSpec.Spec.SLI.BreakdownBy = []string{"route"}
+
+	result, err := NewEvaluator(&breakdownAdapter{}).Evaluate(context.Background(), sloSpec, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Breakdowns) != 2 || len(result.BreakdownErrors) != 0 {
+		t.Fatalf("expected breakdowns of the 5m and 1h windows, got %v (errors %v)", result.Breakdowns, result.BreakdownErrors)
+	}
+	top := result.Breakdowns["1h"]
+	if len(top) != 2 || top[0].Labels["route"] != "/checkout" || top[0].BadEvents != 400 || top[0].Share != 0.8 {
+		t.Errorf("expected /checkout with 400 bad events (80%%) first, got %+v", top)
+	}
+
+	// Each latency_distribution target is broken down with the distribution's labels
+	distribution := parallelTestSLO()
+	distribution.Spec.Objective = 0
+	distribution.Spec.SLI = slo.SLI{
+		Type:        "latency_distribution",
+		BreakdownBy: []string{"route"},
+		Targets: []slo.Target{
+			{ThresholdMs: 200, Objective: 0.99, Good: slo.QueryRef{PrometheusQuery: "fast"}},
+			{ThresholdMs: 1000, Objective: 0.999, Good: slo.QueryRef{PrometheusQuery: "slow"}},
+		},
+		Total: slo.QueryRef{PrometheusQuery: "total"},
+	}
+
+	result, err = NewEvaluator(&breakdownAdapter{}).Evaluate(context.Background(), distribution, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, target := range result.Targets {
+		if len(target.Result.Breakdowns) != 2 {
+			t.Errorf("target %s: expected breakdowns of the 5m and 1h windows, got %v", target.Name, target.Result.Breakdowns)
+		}
+	}
+	if len(result.Breakdowns) != 2 {
+		t.Errorf("expected the worst target's breakdowns at the top level, got %v", result.Breakdowns)
+	}
+
+	// A healthy SLO has no burning windows to break down
+	result, err = NewEvaluator(&partialAdapter{}).Evaluate(context.Background(), sloSpec, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Breakdowns) != 0 || len(result.BreakdownErrors) != 0 {
+		t.Errorf("expected no breakdowns for a healthy SLO, got %v (errors %v)", result.Breakdowns, result.BreakdownErrors)
+	}
+}
@@ -0,0 +1,62 @@
+package eval
+
+// AggregateComposite combines component metrics for one window of a CompositeSLO.
+//
+// weighted_ratio: good = sum(weight * good_i), total = sum(weight * total_i)
+// min: the component with the lowest SLI (components without traffic are ignored)
+//
+// The oldest component timestamp is kept so that one stale component marks the composite stale.
+func AggregateComposite(aggregation string, window string, components []WindowMetrics, weights []float64) WindowMetrics {
+	aggregated := WindowMetrics{Window: window}
+
+	for _, metrics := range components {
+		if metrics.DataTimestamp == nil {
+			continue
+		}
+		if aggregated.DataTimestamp == nil || metrics.DataTimestamp.Before(*aggregated.DataTimestamp) {
+			ts := *metrics.DataTimestamp
+			aggregated.DataTimestamp = &ts
+		}
+	}
+
+	// The aggregate is only as well sampled as its sparsest component
+	for _, metrics := range components {
+		if metrics.SampleCount == nil {
+			continue
+		}
+		if aggregated.SampleCount == nil || *metrics.SampleCount < *aggregated.SampleCount {
+			count := *metrics.SampleCount
+			aggregated.SampleCount = &count
+			aggregated.MaxSamples = metrics.MaxSamples
+		}
+	}
+
+	switch aggregation {
+	case "min":
+		var worstSLI float64
+		found := false
+		for _, metrics := range components {
+			if metrics.Total == 0 {
+				continue
+			}
+			sli := ComputeSLI(metrics.Good, metrics.Total).Value
+			if !found || sli < worstSLI {
+				worstSLI = sli
+				aggregated.Good = metrics.Good
+				aggregated.Total = metrics.Total
+				found = true
+			}
+		}
+	default:
+		for i, metrics := range components {
+			weight := 1.0
+			if i < len(weights) {
+				weight = weights[i]
+			}
+			aggregated.Good += weight * metrics.Good
+			aggregated.Total += weight * metrics.Total
+		}
+	}
+
+	return aggregated
+}
@@ -0,0 +1,64 @@
+package eval
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAggregateComposite(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Minute)
+	many, few := 100, 4
+
+	components := []WindowMetrics{
+		{Good: 990, Total: 1000, DataTimestamp: &newer, SampleCount: &many}, // 99%
+		{Good: 90, Total: 100, DataTimestamp: &older, SampleCount: &few},    // 90%
+		{Good: 0, Total: 0}, // no traffic
+	}
+
+	tests := []struct {
+		name        string
+		aggregation string
+		weights     []float64
+		expectedSLI float64
+	}{
+		{
+			name:        "weighted ratio with equal weights",
+			aggregation: "weighted_ratio",
+			weights:     []float64{1, 1, 1},
+			expectedSLI: 1080.0 / 1100.0,
+		},
+		{
+			name:        "weighted ratio favours heavier component",
+			aggregation: "weighted_ratio",
+			weights:     []float64{1, 10, 1},
+			expectedSLI: (990.0 + 900.0) / (1000.0 + 1000.0),
+		},
+		{
+			name:        "min ignores components without traffic",
+			aggregation: "min",
+			weights:     []float64{1, 1, 1},
+			expectedSLI: 0.9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregated := AggregateComposite(tt.aggregation, "5m", components, tt.weights)
+
+			sli := ComputeSLI(aggregated.Good, aggregated.Total)
+			if math.Abs(sli.Value-tt.expectedSLI) > 0.0001 {
+				t.Errorf("expected SLI=%.4f, got %.4f", tt.expectedSLI, sli.Value)
+			}
+
+			if aggregated.DataTimestamp == nil || !aggregated.DataTimestamp.Equal(older) {
+				t.Errorf("expected oldest component timestamp, got %v", aggregated.DataTimestamp)
+			}
+
+			if aggregated.SampleCount == nil || *aggregated.SampleCount != few {
+				t.Errorf("expected smallest component sample count %d, got %v", few, aggregated.SampleCount)
+			}
+		})
+	}
+}
@@ -1,142 +1,599 @@
 package eval
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/samijaber1/aegis-slo/internal/slo"
+	"golang.org/x/sync/errgroup"
 )
 
 // MetricsAdapter defines the interface for fetching metrics.
-// QueryWindow returns WindowMetrics for the given query+window. For synthetic fixtures,
-// this should return deterministic values. For later Prometheus adapter, this will
-// execute a query with {{window}} substituted.
+// QueryRatio returns the good and total series of a ratio SLI for one window in a
+// single call, so an adapter may batch both queries or compute them in one round trip.
+// Sample counts and per-series timestamps are optional.
+// Implementations must be safe for concurrent use and should abandon the query
+// when ctx is cancelled; the evaluator issues the queries of an evaluation in parallel.
 type MetricsAdapter interface {
-	QueryWindow(query string, window string) (WindowMetrics, error)
+	QueryRatio(ctx context.Context, query RatioQuery) (RatioResult, error)
+}
+
+// RangeAdapter is implemented by adapters that can return the samples of a query
+// over a window ending at end (the current time when zero) at a fixed step.
+// QueryRange substitutes {{window}} with the step, so each sample summarises one
+// step, and combines the series of the query as aggregation says. It is required for
+// time_slice SLIs.
+type RangeAdapter interface {
+	QueryRange(ctx context.Context, query string, window string, step string, end time.Time, aggregation SeriesAggregation) ([]Sample, error)
+}
+
+// SeriesAggregation selects how a range query combines the series it returns at
+// each timestamp
+type SeriesAggregation string
+
+const (
+	// AggregateSum adds the series up, e.g. the good and total counters of a ratio SLI
+	AggregateSum SeriesAggregation = "sum"
+	// AggregateSingle requires the query to return a single series, e.g. a time_slice
+	// gauge whose sum across series would be meaningless
+	AggregateSingle SeriesAggregation = "single"
+)
+
+// LabelValuesAdapter is implemented by adapters that can list the values of a label,
+// optionally restricted to series matching a selector, among the series with samples
+// between start and end. It is required for SLOs that declare expandBy.
+type LabelValuesAdapter interface {
+	LabelValues(ctx context.Context, label string, match string, start, end time.Time) ([]string, error)
+}
+
+// BreakdownAdapter is implemented by adapters that can return the good and total series
+// of a ratio query per group of label values instead of summed. It is used for SLIs
+// that declare breakdownBy; series missing a label are grouped under its empty value.
+type BreakdownAdapter interface {
+	QueryBreakdown(ctx context.Context, query RatioQuery, labels []string) ([]GroupRatio, error)
+}
+
+// QueryStatsReporter is implemented by adapters that cache or deduplicate queries.
+type QueryStatsReporter interface {
+	QueryStats() QueryStats
 }
 
 // Evaluator handles SLO evaluation.
 type Evaluator struct {
-	adapter MetricsAdapter
+	adapter   MetricsAdapter
+	strategy  Strategy
+	rangeStep time.Duration
 }
 
 // NewEvaluator creates a new evaluator with the given metrics adapter.
 func NewEvaluator(adapter MetricsAdapter) *Evaluator {
-	return &Evaluator{adapter: adapter}
+	return &Evaluator{adapter: adapter, strategy: StrategyInstant}
+}
+
+// defaultDiscoveryLookback is the discovery lookback of SLOs without burn rules
+const defaultDiscoveryLookback = time.Hour
+
+// discoveryLookback returns the longest burn window of an SLO, the span over which a
+// label value still affects its gate decision
+func discoveryLookback(sloSpec *slo.SLO) time.Duration {
+	var lookback time.Duration
+	for _, rule := range sloSpec.Spec.BurnPolicy.Rules {
+		for _, window := range []string{rule.ShortWindow, rule.LongWindow} {
+			if d, err := slo.ParseDuration(window); err == nil && d > lookback {
+				lookback = d
+			}
+		}
+	}
+	if lookback == 0 {
+		return defaultDiscoveryLookback
+	}
+	return lookback
+}
+
+// QueryStats returns the adapter's query cache counters, if it keeps any.
+func (e *Evaluator) QueryStats() (QueryStats, bool) {
+	reporter, ok := e.adapter.(QueryStatsReporter)
+	if !ok {
+		return QueryStats{}, false
+	}
+	return reporter.QueryStats(), true
+}
+
+// DiscoverLabelValues lists the label values a dynamic SLO expands into: those reported
+// within its longest burn window, so values that stop being reported are retired once
+// they no longer affect any burn rule.
+func (e *Evaluator) DiscoverLabelValues(ctx context.Context, sloSpec *slo.SLO) ([]string, error) {
+	labelAdapter, ok := e.adapter.(LabelValuesAdapter)
+	if !ok {
+		return nil, fmt.Errorf("SLO %s uses expandBy but the metrics adapter cannot list label values", sloSpec.Metadata.ID)
+	}
+
+	now := time.Now()
+	start := now.Add(-discoveryLookback(sloSpec))
+	values, err := labelAdapter.LabelValues(ctx, sloSpec.Spec.ExpandBy, sloSpec.Spec.ExpandMatch, start, now)
+	if err != nil {
+		return nil, fmt.Errorf("label values for %s: %w", sloSpec.Spec.ExpandBy, err)
+	}
+
+	return values, nil
 }
 
 // Evaluate performs a complete SLO evaluation for a single SLO spec.
-func (e *Evaluator) Evaluate(sloSpec *slo.SLO, now time.Time) (*EvaluationResult, error) {
+// Windows are queried concurrently. Failed queries are reported in the result's
+// QueryErrors rather than as an error; a cancelled ctx abandons the remaining queries.
+func (e *Evaluator) Evaluate(ctx context.Context, sloSpec *slo.SLO, now time.Time) (*EvaluationResult, error) {
 	if sloSpec == nil {
 		return nil, fmt.Errorf("nil sloSpec")
 	}
 
-	result := &EvaluationResult{
-		SLOID:     sloSpec.Metadata.ID,
-		BurnRates: make(map[string]BurnRateResult),
-		Timestamp: now,
+	if sloSpec.Spec.SLI.Type == "latency_distribution" {
+		return e.evaluateDistribution(ctx, sloSpec, now)
+	}
+
+	complianceWindow, period, err := resolveComplianceWindow(sloSpec, now)
+	if err != nil {
+		return nil, err
 	}
 
 	// Collect all unique windows required (compliance + burn policy windows)
-	windows := e.collectWindows(sloSpec)
+	windows := e.collectWindows(sloSpec, complianceWindow)
 
-	// Parse staleness limit once
-	var stalenessLimit time.Duration
-	var haveStalenessLimit bool
-	if sloSpec.Spec.Gating.StalenessLimit != "" {
-		d, err := slo.ParseDuration(sloSpec.Spec.Gating.StalenessLimit)
-		if err == nil {
-			stalenessLimit = d
-			haveStalenessLimit = true
+	// Query metrics for each window
+	windowMetrics, queryErrors, err := e.queryWindows(ctx, sloSpec, windows, now)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := e.buildResult(sloSpec, complianceWindow, period, windowMetrics, queryErrors, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.attachBreakdowns(ctx, sloSpec, result, now); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// EvaluateComposite evaluates a CompositeSLO. Each component's good/total counts are
+// queried over the composite's windows and aggregated per window before the usual
+// SLI, burn rate and budget computation against the composite objective.
+// components maps component SLO IDs to their specs.
+func (e *Evaluator) EvaluateComposite(ctx context.Context, sloSpec *slo.SLO, components map[string]*slo.SLO, now time.Time) (*EvaluationResult, error) {
+	if sloSpec == nil || sloSpec.Spec.Composite == nil {
+		return nil, fmt.Errorf("not a composite SLO")
+	}
+
+	complianceWindow, period, err := resolveComplianceWindow(sloSpec, now)
+	if err != nil {
+		return nil, err
+	}
+
+	composite := sloSpec.Spec.Composite
+	windows := e.collectWindows(sloSpec, complianceWindow)
+
+	weights := make([]float64, len(composite.Components))
+	componentSpecs := make([]*slo.SLO, len(composite.Components))
+	for i, component := range composite.Components {
+		componentSpec, ok := components[component.SLOID]
+		if !ok {
+			return nil, fmt.Errorf("component SLO not found: %s", component.SLOID)
 		}
+		componentSpecs[i] = componentSpec
+		weights[i] = component.EffectiveWeight()
 	}
 
-	// Query metrics for each window
+	// Query every component over every window concurrently
+	componentMetrics := make(map[string][]WindowMetrics, len(windows))
+	for _, window := range windows {
+		componentMetrics[window] = make([]WindowMetrics, len(componentSpecs))
+	}
+	queryErrors := make(map[string]string)
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	for i, componentSpec := range componentSpecs {
+		g.Go(func() error {
+			metrics, errs, err := e.queryWindows(gctx, componentSpec, windows, now)
+			if err != nil {
+				return fmt.Errorf("component %s: %w", componentSpec.Metadata.ID, err)
+			}
+			mu.Lock()
+			for window, m := range metrics {
+				componentMetrics[window][i] = m
+			}
+			for window, msg := range errs {
+				addQueryError(queryErrors, window, fmt.Sprintf("component %s: %s", componentSpec.Metadata.ID, msg))
+			}
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// A window is only aggregated when every component's query succeeded
 	windowMetrics := make(map[string]WindowMetrics, len(windows))
 	for _, window := range windows {
-		// Query good events
-		goodMetrics, err := e.adapter.QueryWindow(sloSpec.Spec.SLI.Good.PrometheusQuery, window)
-		if err != nil {
-			return nil, fmt.Errorf("query good metrics (window=%s): %w", window, err)
+		if _, failed := queryErrors[window]; failed {
+			continue
 		}
+		windowMetrics[window] = AggregateComposite(composite.Aggregation, window, componentMetrics[window], weights)
+	}
+	if len(queryErrors) == 0 {
+		queryErrors = nil
+	}
 
-		// Query total events
-		totalMetrics, err := e.adapter.QueryWindow(sloSpec.Spec.SLI.Total.PrometheusQuery, window)
-		if err != nil {
-			return nil, fmt.Errorf("query total metrics (window=%s): %w", window, err)
+	result, err := e.buildResult(sloSpec, complianceWindow, period, windowMetrics, queryErrors, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, componentSpec := range componentSpecs {
+		if !countsEvents(componentSpec) {
+			result.BudgetEvents = nil
+			break
 		}
+	}
+	return result, nil
+}
 
-		// Choose the best timestamp available for staleness checks:
-		// Prefer the newest timestamp among good/total to avoid marking stale due to one missing/older ts.
-		var chosenTS *time.Time
-		if goodMetrics.DataTimestamp != nil && totalMetrics.DataTimestamp != nil {
-			if goodMetrics.DataTimestamp.After(*totalMetrics.DataTimestamp) {
-				chosenTS = goodMetrics.DataTimestamp
-			} else {
-				chosenTS = totalMetrics.DataTimestamp
-			}
-		} else if goodMetrics.DataTimestamp != nil {
-			chosenTS = goodMetrics.DataTimestamp
-		} else if totalMetrics.DataTimestamp != nil {
-			chosenTS = totalMetrics.DataTimestamp
+// resolveComplianceWindow returns the window queried for the compliance SLI. For
+// calendar windows this is the elapsed part of the current period (at least 1m),
+// returned along with the period itself.
+func resolveComplianceWindow(sloSpec *slo.SLO, now time.Time) (string, *CompliancePeriod, error) {
+	cw := sloSpec.Spec.CalendarWindow
+	if cw == nil {
+		return sloSpec.Spec.ComplianceWindow, nil, nil
+	}
+
+	start, end, err := cw.Period(now)
+	if err != nil {
+		return "", nil, fmt.Errorf("resolve calendar window: %w", err)
+	}
+
+	elapsed := now.Sub(start).Truncate(time.Minute)
+	if elapsed < time.Minute {
+		elapsed = time.Minute
+	}
+
+	period := &CompliancePeriod{
+		Start:           start,
+		End:             end,
+		ElapsedFraction: math.Min(1, float64(elapsed)/float64(end.Sub(start))),
+	}
+
+	return slo.FormatDuration(elapsed), period, nil
+}
+
+// buildResult computes SLI, burn rates, budget and gating modifiers from per-window metrics.
+// Windows whose queries failed are listed in queryErrors and left out of the result.
+func (e *Evaluator) buildResult(sloSpec *slo.SLO, complianceWindow string, period *CompliancePeriod, windowMetrics map[string]WindowMetrics, queryErrors map[string]string, now time.Time) (*EvaluationResult, error) {
+	result := &EvaluationResult{
+		SLOID:            sloSpec.Metadata.ID,
+		BurnRates:        make(map[string]BurnRateResult),
+		Timestamp:        now,
+		ComplianceWindow: complianceWindow,
+		Period:           period,
+		QueryErrors:      queryErrors,
+	}
+
+	// Parse staleness limit once
+	var stalenessLimit time.Duration
+	var haveStalenessLimit bool
+	if sloSpec.Spec.Gating.StalenessLimit != "" {
+		d, err := slo.ParseDuration(sloSpec.Spec.Gating.StalenessLimit)
+		if err == nil {
+			stalenessLimit = d
+			haveStalenessLimit = true
 		}
+	}
 
-		windowMetrics[window] = WindowMetrics{
-			Window:        window,
-			Good:          goodMetrics.Good,
-			Total:         totalMetrics.Total,
-			DataTimestamp: chosenTS,
+	// Staleness gating modifier: if any required window is stale -> result.IsStale = true.
+	// Windows without a data timestamp have unknown freshness.
+	result.Freshness = make(map[string]Freshness, len(windowMetrics))
+	for window, metrics := range windowMetrics {
+		switch {
+		case metrics.DataTimestamp == nil:
+			result.Freshness[window] = FreshnessUnknown
+		case haveStalenessLimit && now.Sub(*metrics.DataTimestamp) > stalenessLimit:
+			result.Freshness[window] = FreshnessStale
+			result.IsStale = true
+		default:
+			result.Freshness[window] = FreshnessFresh
 		}
+	}
 
-		// Staleness gating modifier: if any required window is stale -> result.IsStale = true
-		if haveStalenessLimit && chosenTS != nil {
-			age := now.Sub(*chosenTS)
-			if age > stalenessLimit {
-				result.IsStale = true
+	// Sample count gating modifier: windows whose adapter reported fewer samples than
+	// gating.minDataPoints are insufficient data
+	mins := minSamples(sloSpec.Spec.Gating.MinDataPoints, windowMetrics)
+	for window, metrics := range windowMetrics {
+		if metrics.SampleCount != nil && *metrics.SampleCount < mins[window] {
+			if result.InsufficientSamples == nil {
+				result.InsufficientSamples = make(map[string]int)
+				result.MinSamples = make(map[string]int)
 			}
+			result.InsufficientSamples[window] = *metrics.SampleCount
+			result.MinSamples[window] = mins[window]
+			result.InsufficientData = true
 		}
 	}
 
-	// Compliance window must exist (collectWindows includes it)
-	complianceWindow := sloSpec.Spec.ComplianceWindow
+	// Compliance window must exist (collectWindows includes it) unless its query failed,
+	// in which case the SLI and budget are unknown
 	complianceMetrics, ok := windowMetrics[complianceWindow]
 	if !ok {
-		return nil, fmt.Errorf("missing metrics for compliance window %q", complianceWindow)
+		if _, failed := queryErrors[complianceWindow]; !failed {
+			return nil, fmt.Errorf("missing metrics for compliance window %q", complianceWindow)
+		}
 	}
 
 	// Compute SLI for compliance window (used for budget remaining)
-	result.SLI = ComputeSLI(complianceMetrics.Good, complianceMetrics.Total)
+	if ok {
+		result.SLI = ComputeSLI(complianceMetrics.Good, complianceMetrics.Total)
+	}
 
 	// Compute burn rates + per-window SLI/error rate
 	for window, metrics := range windowMetrics {
 		sliResult := ComputeSLI(metrics.Good, metrics.Total)
 		burnRate := ComputeBurnRate(sliResult.ErrorRate, sloSpec.Spec.Objective)
 
-		result.BurnRates[window] = BurnRateResult{
+		burnRateResult := BurnRateResult{
 			Window:    window,
 			BurnRate:  burnRate,
 			SLI:       sliResult.Value,
 			ErrorRate: sliResult.ErrorRate,
+			Events:    metrics.Total,
+		}
+
+		// Low traffic gating modifier: too few events make the burn rate unreliable
+		if metrics.Total < sloSpec.Spec.Gating.MinEvents {
+			burnRateResult.InsufficientEvents = true
+			result.InsufficientData = true
+		}
+
+		// Confidence mode: report the burn rate implied by the error rate's lower bound
+		if confidence := sloSpec.Spec.Gating.Confidence; confidence > 0 && !sliResult.InsufficientData {
+			errors := math.Max(0, metrics.Total-metrics.Good)
+			lower := ComputeBurnRate(WilsonLowerBound(errors, metrics.Total, confidence), sloSpec.Spec.Objective)
+			burnRateResult.BurnRateLowerBound = &lower
 		}
 
+		result.BurnRates[window] = burnRateResult
+
 		// Insufficient data modifier: if ANY window has total==0, treat evaluation as insufficient
 		if sliResult.InsufficientData {
 			result.InsufficientData = true
 		}
 	}
 
-	// Budget remaining is defined over the compliance window (per PED)
-	result.BudgetRemaining = ComputeBudgetRemaining(result.SLI.ErrorRate, sloSpec.Spec.Objective)
+	// Budget remaining is defined over the compliance window (per PED).
+	// Calendar windows budget for the whole period, so only the elapsed share is consumed.
+	if !ok {
+		return result, nil
+	}
+	elapsedFraction := 1.0
+	if period != nil {
+		result.BudgetRemaining = ComputeCalendarBudgetRemaining(result.SLI.ErrorRate, sloSpec.Spec.Objective, period.ElapsedFraction)
+		period.ProjectedBudgetRemaining = ComputeBudgetRemaining(result.SLI.ErrorRate, sloSpec.Spec.Objective)
+		elapsedFraction = period.ElapsedFraction
+	} else {
+		result.BudgetRemaining = ComputeBudgetRemaining(result.SLI.ErrorRate, sloSpec.Spec.Objective)
+	}
+	if countsEvents(sloSpec) {
+		budgetEvents := ComputeBudgetEvents(complianceMetrics.Good, complianceMetrics.Total, sloSpec.Spec.Objective, elapsedFraction)
+		result.BudgetEvents = &budgetEvents
+	}
+	result.Forecast = ComputeForecast(sloSpec, result, nil)
+
+	return result, nil
+}
+
+// evaluateDistribution evaluates each latency_distribution target as its own
+// latency_threshold SLO. The top-level SLI, burn rates and budget mirror the target
+// with the least budget remaining, preferring targets whose compliance query succeeded;
+// stale/insufficient data from any target propagates.
+func (e *Evaluator) evaluateDistribution(ctx context.Context, sloSpec *slo.SLO, now time.Time) (*EvaluationResult, error) {
+	if len(sloSpec.Spec.SLI.Targets) == 0 {
+		return nil, fmt.Errorf("latency_distribution SLI requires at least one target")
+	}
+
+	result := &EvaluationResult{
+		SLOID:     sloSpec.Metadata.ID,
+		Timestamp: now,
+	}
+
+	var worst *EvaluationResult
+	for _, target := range sloSpec.Spec.SLI.Targets {
+		targetResult, err := e.Evaluate(ctx, sloSpec.ForTarget(target), now)
+		if err != nil {
+			return nil, fmt.Errorf("target %s: %w", target.TargetName(), err)
+		}
+
+		result.Targets = append(result.Targets, TargetResult{
+			Name:        target.TargetName(),
+			ThresholdMs: target.ThresholdMs,
+			Objective:   target.Objective,
+			Result:      targetResult,
+		})
+
+		if targetResult.IsStale {
+			result.IsStale = true
+		}
+		for window, freshness := range targetResult.Freshness {
+			if result.Freshness == nil {
+				result.Freshness = make(map[string]Freshness)
+			}
+			if prev, ok := result.Freshness[window]; !ok || freshness.worse(prev) {
+				result.Freshness[window] = freshness
+			}
+		}
+		if targetResult.InsufficientData {
+			result.InsufficientData = true
+		}
+		for window, count := range targetResult.InsufficientSamples {
+			if result.InsufficientSamples == nil {
+				result.InsufficientSamples = make(map[string]int)
+			}
+			if prev, ok := result.InsufficientSamples[window]; !ok || count < prev {
+				if result.MinSamples == nil {
+					result.MinSamples = make(map[string]int)
+				}
+				result.InsufficientSamples[window] = count
+				result.MinSamples[window] = targetResult.MinSamples[window]
+			}
+		}
+		for window, msg := range targetResult.QueryErrors {
+			if result.QueryErrors == nil {
+				result.QueryErrors = make(map[string]string)
+			}
+			addQueryError(result.QueryErrors, window, fmt.Sprintf("target %s: %s", target.TargetName(), msg))
+		}
+		if worst == nil || worseBudget(targetResult, worst) {
+			worst = targetResult
+		}
+	}
+
+	result.SLI = worst.SLI
+	result.BurnRates = worst.BurnRates
+	result.BudgetRemaining = worst.BudgetRemaining
+	result.ComplianceWindow = worst.ComplianceWindow
+	result.Period = worst.Period
+	result.Forecast = worst.Forecast
+	result.BudgetEvents = worst.BudgetEvents
+	result.Breakdowns = worst.Breakdowns
+	result.BreakdownErrors = worst.BreakdownErrors
 
 	return result, nil
 }
 
+// countsEvents reports whether the window values of an SLI are event counts, so its
+// budget can be counted in bad events: time slices are, and good and total queries are
+// when sli.countsEvents says so. Composites are decided by EvaluateComposite from their
+// components.
+func countsEvents(sloSpec *slo.SLO) bool {
+	if sloSpec.Spec.SLI.Type == "time_slice" || sloSpec.Spec.Composite != nil {
+		return true
+	}
+	return sloSpec.Spec.SLI.CountsEvents
+}
+
+// worseBudget reports whether a has less budget remaining than b. A result whose
+// compliance query failed has no budget and is only worse than another such result.
+func worseBudget(a, b *EvaluationResult) bool {
+	_, aUnknown := a.QueryErrors[a.ComplianceWindow]
+	_, bUnknown := b.QueryErrors[b.ComplianceWindow]
+	if aUnknown != bUnknown {
+		return bUnknown
+	}
+	return a.BudgetRemaining < b.BudgetRemaining
+}
+
+// queryWindow fetches good/total metrics for a single window ending at the evaluation
+// time according to the SLI type.
+func (e *Evaluator) queryWindow(ctx context.Context, sloSpec *slo.SLO, window string, at time.Time) (WindowMetrics, error) {
+	if sloSpec.Spec.SLI.Type == "time_slice" {
+		return e.queryTimeSlices(ctx, sloSpec, window, at)
+	}
+
+	result, err := e.adapter.QueryRatio(ctx, RatioQuery{
+		Good:   sloSpec.Spec.SLI.Good.PrometheusQuery,
+		Total:  sloSpec.Spec.SLI.Total.PrometheusQuery,
+		Window: window,
+		Time:   at,
+	})
+	if err != nil {
+		return WindowMetrics{}, fmt.Errorf("query ratio (window=%s): %w", window, err)
+	}
+
+	return ratioToWindowMetrics(window, result), nil
+}
+
+// ratioToWindowMetrics flattens a ratio result. The newest series timestamp is kept
+// for staleness checks so one missing or older timestamp does not mark the window stale;
+// the smaller sample count is kept since either series can be the sparse one.
+func ratioToWindowMetrics(window string, result RatioResult) WindowMetrics {
+	metrics := WindowMetrics{
+		Window: window,
+		Good:   result.Good.Value,
+		Total:  result.Total.Value,
+	}
+
+	good, total := result.Good.Timestamp, result.Total.Timestamp
+	switch {
+	case good != nil && total != nil:
+		if good.After(*total) {
+			metrics.DataTimestamp = good
+		} else {
+			metrics.DataTimestamp = total
+		}
+	case good != nil:
+		metrics.DataTimestamp = good
+	case total != nil:
+		metrics.DataTimestamp = total
+	}
+
+	goodCount, totalCount := result.Good.SampleCount, result.Total.SampleCount
+	switch {
+	case goodCount != nil && totalCount != nil:
+		if *goodCount < *totalCount {
+			metrics.SampleCount, metrics.MaxSamples = goodCount, result.Good.MaxSamples
+		} else {
+			metrics.SampleCount, metrics.MaxSamples = totalCount, result.Total.MaxSamples
+		}
+	case goodCount != nil:
+		metrics.SampleCount, metrics.MaxSamples = goodCount, result.Good.MaxSamples
+	case totalCount != nil:
+		metrics.SampleCount, metrics.MaxSamples = totalCount, result.Total.MaxSamples
+	}
+
+	return metrics
+}
+
+// queryTimeSlices fetches the slices of a time_slice SLI over a window and
+// reports good slices as Good and observed slices as Total.
+func (e *Evaluator) queryTimeSlices(ctx context.Context, sloSpec *slo.SLO, window string, at time.Time) (WindowMetrics, error) {
+	ts := sloSpec.Spec.SLI.TimeSlice
+	if ts == nil {
+		return WindowMetrics{}, fmt.Errorf("time_slice SLI requires sli.timeSlice")
+	}
+
+	rangeAdapter, ok := e.adapter.(RangeAdapter)
+	if !ok {
+		return WindowMetrics{}, fmt.Errorf("metrics adapter does not support range queries required by time_slice SLIs")
+	}
+
+	samples, err := rangeAdapter.QueryRange(ctx, ts.Query.PrometheusQuery, window, ts.Slice, at, AggregateSingle)
+	if err != nil {
+		return WindowMetrics{}, fmt.Errorf("query time slices (window=%s): %w", window, err)
+	}
+
+	good, total := ComputeTimeSlices(samples, ts.Target, ts.Comparison)
+
+	var latest *time.Time
+	for i := range samples {
+		if latest == nil || samples[i].Timestamp.After(*latest) {
+			latest = &samples[i].Timestamp
+		}
+	}
+
+	return WindowMetrics{
+		Window:        window,
+		Good:          good,
+		Total:         total,
+		DataTimestamp: latest,
+	}, nil
+}
+
 // collectWindows extracts all unique windows from burn policy rules.
-func (e *Evaluator) collectWindows(sloSpec *slo.SLO) []string {
+func (e *Evaluator) collectWindows(sloSpec *slo.SLO, complianceWindow string) []string {
 	windowSet := make(map[string]struct{})
 
 	// Add compliance window
-	windowSet[sloSpec.Spec.ComplianceWindow] = struct{}{}
+	windowSet[complianceWindow] = struct{}{}
 
 	// Add all burn policy windows
 	for _, rule := range sloSpec.Spec.BurnPolicy.Rules {
@@ -151,3 +608,11 @@ func (e *Evaluator) collectWindows(sloSpec *slo.SLO) []string {
 	}
 	return windows
 }
+
+// addQueryError records a query error for a window, joining it to any already recorded
+func addQueryError(queryErrors map[string]string, window string, msg string) {
+	if existing, ok := queryErrors[window]; ok {
+		msg = existing + "; " + msg
+	}
+	queryErrors[window] = msg
+}
@@ -0,0 +1,262 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/slo"
+)
+
+// blockingAdapter holds every query until release is closed or ctx is cancelled
+type blockingAdapter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	started int
+	arrived chan struct{}
+	expect  int
+}
+
+func (b *blockingAdapter) QueryRatio(ctx context.Context, query RatioQuery) (RatioResult, error) {
+	b.mu.Lock()
+	b.started++
+	if b.started == b.expect {
+		close(b.arrived)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return RatioResult{}, ctx.Err()
+	case <-b.release:
+		return RatioResult{Window: query.Window, Good: SeriesValue{Value: 999}, Total: SeriesValue{Value: 1000}}, nil
+	}
+}
+
+func parallelTestSLO() *slo.SLO {
+	return &slo.SLO{
+		Metadata: slo.Metadata{ID: "parallel"},
+		Spec: slo.Spec{
+			Objective:        0.99,
+			ComplianceWindow: "30d",
+			SLI: slo.SLI{
+				Type:         "ratio",
+				Good:         slo.QueryRef{PrometheusQuery: "good"},
+				Total:        slo.QueryRef{PrometheusQuery: "total"},
+				CountsEvents: true,
+			},
+			BurnPolicy: slo.BurnPolicy{Rules: []slo.BurnRule{
+				{ShortWindow: "5m", LongWindow: "1h", Threshold: 14, Action: "BLOCK"},
+			}},
+		},
+	}
+}
+
+func TestEvaluator_QueriesWindowsConcurrently(t *testing.T) {
+	// 3 windows: all 3 ratio queries must be in flight at once
+	adapter := &blockingAdapter{release: make(chan struct{}), arrived: make(chan struct{}), expect: 3}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := NewEvaluator(adapter).Evaluate(context.Background(), parallelTestSLO(), time.Now())
+		done <- err
+	}()
+
+	select {
+	case <-adapter.arrived:
+	case <-time.After(2 * time.Second):
+		t.Fatal("queries were not issued concurrently")
+	}
+	close(adapter.release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEvaluator_CancelAbandonsQueries(t *testing.T) {
+	adapter := &blockingAdapter{release: make(chan struct{}), arrived: make(chan struct{}), expect: 3}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := NewEvaluator(adapter).Evaluate(ctx, parallelTestSLO(), time.Now())
+		done <- err
+	}()
+
+	<-adapter.arrived
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("evaluation did not return after cancellation")
+	}
+}
+
+// partialAdapter fails queries for the windows in fail and answers the rest
+type partialAdapter struct {
+	fail map[string]bool
+}
+
+func (p *partialAdapter) QueryRatio(ctx context.Context, query RatioQuery) (RatioResult, error) {
+	if p.fail[query.Window] {
+		return RatioResult{}, errors.New("http status 503")
+	}
+	now := time.Now()
+	return RatioResult{
+		Window: query.Window,
+		Good:   SeriesValue{Value: 999, Timestamp: &now},
+		Total:  SeriesValue{Value: 1000, Timestamp: &now},
+	}, nil
+}
+
+func TestEvaluator_ReportsQueryErrorsPerWindow(t *testing.T) {
+	adapter := &partialAdapter{fail: map[string]bool{"1h": true}}
+
+	result, err := NewEvaluator(adapter).Evaluate(context.Background(), parallelTestSLO(), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Failed() {
+		t.Error("expected the result to be marked failed")
+	}
+	if len(result.QueryErrors) != 1 || result.QueryErrors["1h"] == "" {
+		t.Errorf("expected a query error for 1h only, got %v", result.QueryErrors)
+	}
+	if _, ok := result.BurnRates["1h"]; ok {
+		t.Error("expected no burn rate for the failed window")
+	}
+	if _, ok := result.BurnRates["5m"]; !ok {
+		t.Error("expected the 5m burn rate to be kept")
+	}
+	if result.SLI.Value == 0 {
+		t.Error("expected the SLI to be computed from the compliance window")
+	}
+	if budget := result.BudgetEvents; budget == nil || math.Abs(budget.Allowed-10) > 0.001 || budget.Consumed != 1 {
+		t.Errorf("expected 10 allowed and 1 consumed bad events, got %+v", budget)
+	}
+}
+
+func TestEvaluator_BudgetEventsRequireEventCounts(t *testing.T) {
+	tests := []struct {
+		name         string
+		countsEvents bool
+		expected     bool
+	}{
+		{name: "event counts", countsEvents: true, expected: true},
+		{name: "per-second rates", countsEvents: false, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sloSpec := parallelTestSLO()
+			sloSpec.Spec.SLI.CountsEvents = tt.countsEvents
+
+			result, err := NewEvaluator(&partialAdapter{}).Evaluate(context.Background(), sloSpec, time.Now())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (result.BudgetEvents != nil) != tt.expected {
+				t.Errorf("expected budget events reported=%v, got %+v", tt.expected, result.BudgetEvents)
+			}
+		})
+	}
+}
+
+// timestampAdapter answers every window with a healthy ratio whose data timestamp is
+// looked up by window; windows without one report no timestamp
+type timestampAdapter struct {
+	timestamps map[string]time.Time
+}
+
+func (a *timestampAdapter) QueryRatio(ctx context.Context, query RatioQuery) (RatioResult, error) {
+	var ts *time.Time
+	if t, ok := a.timestamps[query.Window]; ok {
+		ts = &t
+	}
+	return RatioResult{
+		Window: query.Window,
+		Good:   SeriesValue{Value: 999, Timestamp: ts},
+		Total:  SeriesValue{Value: 1000, Timestamp: ts},
+	}, nil
+}
+
+func TestEvaluator_WindowFreshness(t *testing.T) {
+	now := time.Now()
+	adapter := &timestampAdapter{timestamps: map[string]time.Time{
+		"5m":  now.Add(-30 * time.Second),
+		"30d": now.Add(-time.Hour),
+	}}
+
+	sloSpec := parallelTestSLO()
+	sloSpec.Spec.Gating.StalenessLimit = "120s"
+
+	result, err := NewEvaluator(adapter).Evaluate(context.Background(), sloSpec, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]Freshness{
+		"5m":  FreshnessFresh,
+		"1h":  FreshnessUnknown,
+		"30d": FreshnessStale,
+	}
+	for window, freshness := range expected {
+		if result.Freshness[window] != freshness {
+			t.Errorf("window %s: expected %s, got %s", window, freshness, result.Freshness[window])
+		}
+	}
+	if !result.IsStale {
+		t.Error("expected the stale window to mark the result stale")
+	}
+	if windows := result.UnknownFreshnessWindows(); len(windows) != 1 || windows[0] != "1h" {
+		t.Errorf("expected 1h as the only unknown window, got %v", windows)
+	}
+}
+
+func TestRatioToWindowMetrics(t *testing.T) {
+	older := time.Now().Add(-time.Minute)
+	newer := time.Now()
+	few, many := 3, 10
+
+	metrics := ratioToWindowMetrics("5m", RatioResult{
+		Window: "5m",
+		Good:   SeriesValue{Value: 99, SampleCount: &many, Timestamp: &older},
+		Total:  SeriesValue{Value: 100, SampleCount: &few, Timestamp: &newer},
+	})
+
+	if metrics.Good != 99 || metrics.Total != 100 {
+		t.Errorf("expected good=99 total=100, got good=%f total=%f", metrics.Good, metrics.Total)
+	}
+	if metrics.DataTimestamp == nil || !metrics.DataTimestamp.Equal(newer) {
+		t.Errorf("expected newest series timestamp, got %v", metrics.DataTimestamp)
+	}
+	if metrics.SampleCount == nil || *metrics.SampleCount != few {
+		t.Errorf("expected smallest sample count %d, got %v", few, metrics.SampleCount)
+	}
+
+	empty := ratioToWindowMetrics("5m", RatioResult{Window: "5m"})
+	if empty.DataTimestamp != nil || empty.SampleCount != nil {
+		t.Error("expected optional fields to stay unset when adapters omit them")
+	}
+}
+
+func TestDiscoveryLookback(t *testing.T) {
+	sloSpec := strategySLO()
+	if got := discoveryLookback(sloSpec); got != 6*time.Hour {
+		t.Errorf("expected the longest burn window 6h, got %s", got)
+	}
+
+	sloSpec.Spec.BurnPolicy.Rules = nil
+	if got := discoveryLookback(sloSpec); got != defaultDiscoveryLookback {
+		t.Errorf("expected the default lookback without burn rules, got %s", got)
+	}
+}
@@ -0,0 +1,146 @@
+package eval
+
+import (
+	"math"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/slo"
+)
+
+// Forecast bases
+const (
+	ForecastBasisBurnRate = "burn_rate" // burn rate of the longest burn window
+	ForecastBasisHistory  = "history"   // trend of budget remaining in recent evaluations
+)
+
+// Forecast projects error budget consumption at the current pace
+type Forecast struct {
+	Basis    string
+	Window   string  // burn window the pace was taken from, or the one history covers
+	BurnRate float64 // pace as a burn rate: 1 consumes the whole budget over the compliance window
+	// Time until the budget is exhausted at this pace; nil when it is not being consumed
+	TimeToExhaustion *time.Duration
+	// Budget remaining at the end of the calendar period, or one rolling compliance window
+	// ahead: the budget left once the window only covers the current burn rate, or the
+	// history trend extrapolated
+	ProjectedBudgetRemaining float64
+}
+
+// BudgetPoint is the budget remaining of a past evaluation
+type BudgetPoint struct {
+	Timestamp       time.Time
+	BudgetRemaining float64
+}
+
+// ComputeForecast projects the budget of an evaluation. The pace is the trend of budget
+// remaining over history and the current result when history holds at least two earlier
+// points, and the burn rate of the longest burn window otherwise. Returns nil when the
+// budget is unknown.
+func ComputeForecast(sloSpec *slo.SLO, result *EvaluationResult, history []BudgetPoint) *Forecast {
+	if result.Error != "" {
+		return nil
+	}
+	if _, failed := result.QueryErrors[result.ComplianceWindow]; failed {
+		return nil
+	}
+
+	budgetWindow, err := forecastBudgetWindow(sloSpec, result)
+	if err != nil || budgetWindow <= 0 {
+		return nil
+	}
+
+	window := forecastWindow(sloSpec, result)
+	forecast := &Forecast{Basis: ForecastBasisBurnRate, Window: window}
+	if br, ok := result.BurnRates[window]; ok {
+		forecast.BurnRate = br.BurnRate
+	}
+
+	if len(history) >= 2 {
+		points := append(append([]BudgetPoint(nil), history...), BudgetPoint{
+			Timestamp:       result.Timestamp,
+			BudgetRemaining: result.BudgetRemaining,
+		})
+		if slope, ok := budgetSlope(points); ok {
+			forecast.Basis = ForecastBasisHistory
+			forecast.BurnRate = math.Max(0, -slope*budgetWindow.Seconds())
+		}
+	}
+
+	remaining := result.BudgetRemaining
+	switch {
+	case remaining <= 0:
+		exhausted := time.Duration(0)
+		forecast.TimeToExhaustion = &exhausted
+	case forecast.BurnRate > 0:
+		ttl := time.Duration(remaining / forecast.BurnRate * float64(budgetWindow))
+		forecast.TimeToExhaustion = &ttl
+	}
+
+	switch {
+	case result.Period != nil:
+		left := result.Period.End.Sub(result.Timestamp)
+		forecast.ProjectedBudgetRemaining = clampBudget(remaining - forecast.BurnRate*float64(left)/float64(budgetWindow))
+	case forecast.Basis == ForecastBasisHistory:
+		forecast.ProjectedBudgetRemaining = clampBudget(remaining - forecast.BurnRate)
+	default:
+		forecast.ProjectedBudgetRemaining = clampBudget(1 - forecast.BurnRate)
+	}
+
+	return forecast
+}
+
+// forecastBudgetWindow returns the span the error budget covers
+func forecastBudgetWindow(sloSpec *slo.SLO, result *EvaluationResult) (time.Duration, error) {
+	if period := result.Period; period != nil {
+		return period.End.Sub(period.Start), nil
+	}
+	return slo.ParseDuration(sloSpec.Spec.ComplianceWindow)
+}
+
+// forecastWindow picks the longest burn window evaluated, falling back to the compliance
+// window when the SLO has no burn rules
+func forecastWindow(sloSpec *slo.SLO, result *EvaluationResult) string {
+	var longest string
+	var longestDur time.Duration
+	for _, rule := range sloSpec.Spec.BurnPolicy.Rules {
+		for _, window := range []string{rule.ShortWindow, rule.LongWindow} {
+			if _, ok := result.BurnRates[window]; !ok {
+				continue
+			}
+			d, err := slo.ParseDuration(window)
+			if err == nil && d > longestDur {
+				longest, longestDur = window, d
+			}
+		}
+	}
+	if longest == "" {
+		return result.ComplianceWindow
+	}
+	return longest
+}
+
+// budgetSlope fits budget remaining over time by least squares and returns the slope per
+// second. It fails when all points share a timestamp.
+func budgetSlope(points []BudgetPoint) (float64, bool) {
+	origin := points[0].Timestamp
+	var sumX, sumY, sumXY, sumXX float64
+	for _, p := range points {
+		x := p.Timestamp.Sub(origin).Seconds()
+		sumX += x
+		sumY += p.BudgetRemaining
+		sumXY += x * p.BudgetRemaining
+		sumXX += x * x
+	}
+
+	n := float64(len(points))
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0, false
+	}
+	return (n*sumXY - sumX*sumY) / denom, true
+}
+
+// clampBudget limits a budget fraction to [0, 1]
+func clampBudget(budget float64) float64 {
+	return math.Min(1, math.Max(0, budget))
+}
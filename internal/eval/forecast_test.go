@@ -0,0 +1,148 @@
+package eval
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/slo"
+)
+
+func forecastTestSLO() *slo.SLO {
+	return &slo.SLO{
+		Metadata: slo.Metadata{ID: "forecast"},
+		Spec: slo.Spec{
+			Objective:        0.99,
+			ComplianceWindow: "30d",
+			BurnPolicy: slo.BurnPolicy{Rules: []slo.BurnRule{
+				{ShortWindow: "5m", LongWindow: "1h", Threshold: 14, Action: "BLOCK"},
+				{ShortWindow: "1h", LongWindow: "6h", Threshold: 6, Action: "WARN"},
+			}},
+		},
+	}
+}
+
+func TestComputeForecast(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	burnRates := func(sixHour float64) map[string]BurnRateResult {
+		return map[string]BurnRateResult{
+			"5m": {BurnRate: 20},
+			"1h": {BurnRate: 10},
+			"6h": {BurnRate: sixHour},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		result            *EvaluationResult
+		history           []BudgetPoint
+		expectedBasis     string
+		expectedBurnRate  float64
+		expectedTTE       *time.Duration
+		expectedProjected float64
+	}{
+		{
+			name: "burn rate of the longest burn window",
+			result: &EvaluationResult{
+				ComplianceWindow: "30d", Timestamp: now, BudgetRemaining: 0.5, BurnRates: burnRates(2),
+			},
+			expectedBasis:     ForecastBasisBurnRate,
+			expectedBurnRate:  2,
+			expectedTTE:       durationPtr(7*day + 12*time.Hour), // 0.5 / 2 * 30d
+			expectedProjected: 0,
+		},
+		{
+			name: "no consumption",
+			result: &EvaluationResult{
+				ComplianceWindow: "30d", Timestamp: now, BudgetRemaining: 0.9, BurnRates: burnRates(0),
+			},
+			expectedBasis:     ForecastBasisBurnRate,
+			expectedProjected: 1,
+		},
+		{
+			name: "exhausted",
+			result: &EvaluationResult{
+				ComplianceWindow: "30d", Timestamp: now, BudgetRemaining: 0, BurnRates: burnRates(0.5),
+			},
+			expectedBasis:     ForecastBasisBurnRate,
+			expectedBurnRate:  0.5,
+			expectedTTE:       durationPtr(0),
+			expectedProjected: 0.5,
+		},
+		{
+			name: "history trend",
+			result: &EvaluationResult{
+				ComplianceWindow: "30d", Timestamp: now, BudgetRemaining: 0.5, BurnRates: burnRates(2),
+			},
+			// Budget falls by 0.1 per day: a burn rate of 3 against a 30d window
+			history: []BudgetPoint{
+				{Timestamp: now.Add(-2 * day), BudgetRemaining: 0.7},
+				{Timestamp: now.Add(-day), BudgetRemaining: 0.6},
+			},
+			expectedBasis:     ForecastBasisHistory,
+			expectedBurnRate:  3,
+			expectedTTE:       durationPtr(5 * day),
+			expectedProjected: 0,
+		},
+		{
+			name: "calendar period",
+			result: &EvaluationResult{
+				ComplianceWindow: "9d", Timestamp: now, BudgetRemaining: 0.8, BurnRates: burnRates(0.5),
+				Period: &CompliancePeriod{
+					Start: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+					End:   time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			expectedBasis:     ForecastBasisBurnRate,
+			expectedBurnRate:  0.5,
+			expectedTTE:       durationPtr(48 * day), // 0.8 / 0.5 * 30d
+			expectedProjected: 0.8 - 0.5*21.0/30.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast := ComputeForecast(forecastTestSLO(), tt.result, tt.history)
+			if forecast == nil {
+				t.Fatal("expected a forecast")
+			}
+
+			if forecast.Basis != tt.expectedBasis || forecast.Window != "6h" {
+				t.Errorf("expected basis %s over 6h, got %s over %s", tt.expectedBasis, forecast.Basis, forecast.Window)
+			}
+			if math.Abs(forecast.BurnRate-tt.expectedBurnRate) > 0.0001 {
+				t.Errorf("expected burn rate %.4f, got %.4f", tt.expectedBurnRate, forecast.BurnRate)
+			}
+			switch {
+			case tt.expectedTTE == nil && forecast.TimeToExhaustion != nil:
+				t.Errorf("expected no exhaustion, got %s", *forecast.TimeToExhaustion)
+			case tt.expectedTTE != nil && forecast.TimeToExhaustion == nil:
+				t.Errorf("expected exhaustion in %s, got none", *tt.expectedTTE)
+			case tt.expectedTTE != nil && (*forecast.TimeToExhaustion-*tt.expectedTTE).Abs() > time.Minute:
+				t.Errorf("expected exhaustion in %s, got %s", *tt.expectedTTE, *forecast.TimeToExhaustion)
+			}
+			if math.Abs(forecast.ProjectedBudgetRemaining-tt.expectedProjected) > 0.0001 {
+				t.Errorf("expected projected budget %.4f, got %.4f", tt.expectedProjected, forecast.ProjectedBudgetRemaining)
+			}
+		})
+	}
+}
+
+func TestComputeForecast_UnknownBudget(t *testing.T) {
+	results := []*EvaluationResult{
+		{Error: "component SLO not found: api"},
+		{ComplianceWindow: "30d", QueryErrors: map[string]string{"30d": "timeout"}},
+	}
+
+	for _, result := range results {
+		if forecast := ComputeForecast(forecastTestSLO(), result, nil); forecast != nil {
+			t.Errorf("expected no forecast for %+v, got %+v", result, forecast)
+		}
+	}
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
@@ -1,6 +1,9 @@
 package eval_test
 
 import (
+	"context"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,6 +23,10 @@ func TestScenarios(t *testing.T) {
 		expectedDecision policy.Decision
 		checkStale       bool
 		checkNoTraffic   bool
+		minDataPoints    int
+		minEvents        float64
+		confidence       float64
+		expectedReason   string
 	}{
 		{
 			name:             "healthy",
@@ -30,6 +37,7 @@ func TestScenarios(t *testing.T) {
 			name:             "fast-burn",
 			fixtureFile:      "../../fixtures/metrics/fast-burn.json",
 			expectedDecision: policy.DecisionBLOCK,
+			expectedReason:   `rule fast-burn triggered: short=20.00x, long=20.00x (threshold=14.00x); top contributor: route="/checkout" (75% of bad events)`,
 		},
 		{
 			name:             "slow-burn",
@@ -48,6 +56,33 @@ func TestScenarios(t *testing.T) {
 			expectedDecision: policy.DecisionWARN,
 			checkNoTraffic:   true,
 		},
+		{
+			name:             "sparse",
+			fixtureFile:      "../../fixtures/metrics/sparse.json",
+			expectedDecision: policy.DecisionWARN,
+			checkNoTraffic:   true,
+			minDataPoints:    10,
+			expectedReason:   "insufficient data for window 5m: 3 samples, minimum 10",
+		},
+		{
+			name:             "low-traffic",
+			fixtureFile:      "../../fixtures/metrics/low-traffic.json",
+			expectedDecision: policy.DecisionBLOCK,
+		},
+		{
+			name:             "low-traffic-min-events",
+			fixtureFile:      "../../fixtures/metrics/low-traffic.json",
+			expectedDecision: policy.DecisionWARN,
+			checkNoTraffic:   true,
+			minEvents:        100,
+			expectedReason:   "insufficient events for window 5m: 40 events, minimum 100",
+		},
+		{
+			name:             "low-traffic-confidence",
+			fixtureFile:      "../../fixtures/metrics/low-traffic.json",
+			expectedDecision: policy.DecisionALLOW,
+			confidence:       0.95,
+		},
 	}
 
 	for _, tt := range tests {
@@ -58,12 +93,23 @@ func TestScenarios(t *testing.T) {
 				t.Fatalf("failed to load fixture: %v", err)
 			}
 
+			sloSpec := sloSpec
+			if tt.minDataPoints > 0 || tt.minEvents > 0 || tt.confidence > 0 {
+				spec := *sloSpec
+				if tt.minDataPoints > 0 {
+					spec.Spec.Gating.MinDataPoints = tt.minDataPoints
+				}
+				spec.Spec.Gating.MinEvents = tt.minEvents
+				spec.Spec.Gating.Confidence = tt.confidence
+				sloSpec = &spec
+			}
+
 			// Create evaluator
 			evaluator := eval.NewEvaluator(adapter)
 
 			// Evaluate SLO
 			now := time.Now()
-			evalResult, err := evaluator.Evaluate(sloSpec, now)
+			evalResult, err := evaluator.Evaluate(context.Background(), sloSpec, now)
 			if err != nil {
 				t.Fatalf("evaluation failed: %v", err)
 			}
@@ -89,6 +135,10 @@ func TestScenarios(t *testing.T) {
 				t.Error("expected HasNoTraffic to be true")
 			}
 
+			if tt.expectedReason != "" && !slices.Contains(gateResult.Reasons, tt.expectedReason) {
+				t.Errorf("expected reason %q, got %v", tt.expectedReason, gateResult.Reasons)
+			}
+
 			t.Logf("Decision: %s, Reasons: %v", gateResult.Decision, gateResult.Reasons)
 			for _, rr := range gateResult.RuleResults {
 				if rr.Triggered {
@@ -125,3 +175,257 @@ func loadCheckoutSLO(t *testing.T) *slo.SLO {
 	t.Fatal("checkout-availability SLO not found")
 	return nil
 }
+
+func TestLatencyDistribution(t *testing.T) {
+	adapter := synthetic.NewAdapter()
+	windows := func(good, total float64) *synthetic.MetricFixture {
+		return &synthetic.MetricFixture{Windows: map[string]synthetic.WindowData{
+			"5m":  {Good: good, Total: total},
+			"1h":  {Good: good, Total: total},
+			"30d": {Good: good, Total: total},
+		}}
+	}
+	// p90 target healthy (95% under 200ms), p99 target burning (80% under 1s)
+	adapter.SetFixture("fast", windows(950, 1000))
+	adapter.SetFixture("slow", windows(800, 1000))
+	adapter.SetFixture("total", windows(1000, 1000))
+
+	sloSpec := &slo.SLO{
+		Metadata: slo.Metadata{ID: "latency"},
+		Spec: slo.Spec{
+			ComplianceWindow: "30d",
+			SLI: slo.SLI{
+				Type: "latency_distribution",
+				Targets: []slo.Target{
+					{ThresholdMs: 200, Objective: 0.9, Good: slo.QueryRef{PrometheusQuery: "fast"}},
+					{ThresholdMs: 1000, Objective: 0.99, Good: slo.QueryRef{PrometheusQuery: "slow"}},
+				},
+				Total: slo.QueryRef{PrometheusQuery: "total"},
+			},
+			BurnPolicy: slo.BurnPolicy{
+				Rules: []slo.BurnRule{
+					{Name: "fast-burn", ShortWindow: "5m", LongWindow: "1h", Threshold: 14, Action: "BLOCK"},
+				},
+			},
+		},
+	}
+
+	evalResult, err := eval.NewEvaluator(adapter).Evaluate(context.Background(), sloSpec, time.Now())
+	if err != nil {
+		t.Fatalf("evaluation failed: %v", err)
+	}
+
+	if len(evalResult.Targets) != 2 {
+		t.Fatalf("expected 2 target results, got %d", len(evalResult.Targets))
+	}
+
+	if br := evalResult.Targets[0].Result.BurnRates["5m"].BurnRate; br > 1 {
+		t.Errorf("expected 200ms target to burn below 1x, got %.2f", br)
+	}
+
+	// Top level mirrors the worst target (1000ms: 20% errors / 1% budget = 20x)
+	if br := evalResult.BurnRates["5m"].BurnRate; br < 19.99 || br > 20.01 {
+		t.Errorf("expected top-level 5m burn rate=20, got %.2f", br)
+	}
+
+	gateResult := policy.NewEngine().Evaluate(sloSpec, evalResult)
+	if gateResult.Decision != policy.DecisionBLOCK {
+		t.Errorf("expected BLOCK, got %s (reasons: %v)", gateResult.Decision, gateResult.Reasons)
+	}
+
+	if len(gateResult.Reasons) != 1 || !strings.HasPrefix(gateResult.Reasons[0], "target 1000ms") {
+		t.Errorf("expected a single reason naming the 1000ms target, got %v", gateResult.Reasons)
+	}
+}
+
+func TestLatencyDistribution_FailedComplianceQuery(t *testing.T) {
+	adapter := synthetic.NewAdapter()
+	// The 200ms target spends half its budget; the 1000ms target has no 30d data
+	adapter.SetFixture("fast", &synthetic.MetricFixture{Windows: map[string]synthetic.WindowData{
+		"5m":  {Good: 950, Total: 1000},
+		"1h":  {Good: 950, Total: 1000},
+		"30d": {Good: 950, Total: 1000},
+	}})
+	adapter.SetFixture("slow", &synthetic.MetricFixture{Windows: map[string]synthetic.WindowData{
+		"5m": {Good: 990, Total: 1000},
+		"1h": {Good: 990, Total: 1000},
+	}})
+	adapter.SetFixture("total", &synthetic.MetricFixture{Windows: map[string]synthetic.WindowData{
+		"5m":  {Good: 1000, Total: 1000},
+		"1h":  {Good: 1000, Total: 1000},
+		"30d": {Good: 1000, Total: 1000},
+	}})
+
+	sloSpec := &slo.SLO{
+		Metadata: slo.Metadata{ID: "latency"},
+		Spec: slo.Spec{
+			ComplianceWindow: "30d",
+			SLI: slo.SLI{
+				Type: "latency_distribution",
+				Targets: []slo.Target{
+					{ThresholdMs: 200, Objective: 0.9, Good: slo.QueryRef{PrometheusQuery: "fast"}},
+					{ThresholdMs: 1000, Objective: 0.99, Good: slo.QueryRef{PrometheusQuery: "slow"}},
+				},
+				Total: slo.QueryRef{PrometheusQuery: "total"},
+			},
+			BurnPolicy: slo.BurnPolicy{
+				Rules: []slo.BurnRule{
+					{Name: "fast-burn", ShortWindow: "5m", LongWindow: "1h", Threshold: 14, Action: "BLOCK"},
+				},
+			},
+		},
+	}
+
+	evalResult, err := eval.NewEvaluator(adapter).Evaluate(context.Background(), sloSpec, time.Now())
+	if err != nil {
+		t.Fatalf("evaluation failed: %v", err)
+	}
+
+	// The target without budget data is not taken as the worst
+	if evalResult.BudgetRemaining < 0.49 || evalResult.BudgetRemaining > 0.51 {
+		t.Errorf("expected the 200ms target's budget remaining=0.5, got %.2f", evalResult.BudgetRemaining)
+	}
+	if _, failed := evalResult.QueryErrors["30d"]; !failed {
+		t.Errorf("expected the 30d query error to propagate, got %v", evalResult.QueryErrors)
+	}
+
+	// With no budget data at all the failed target is still mirrored
+	adapter.SetFixture("fast", &synthetic.MetricFixture{Windows: map[string]synthetic.WindowData{
+		"5m": {Good: 950, Total: 1000},
+		"1h": {Good: 950, Total: 1000},
+	}})
+	evalResult, err = eval.NewEvaluator(adapter).Evaluate(context.Background(), sloSpec, time.Now())
+	if err != nil {
+		t.Fatalf("evaluation failed: %v", err)
+	}
+	if len(evalResult.BurnRates) == 0 {
+		t.Error("expected the burn rates of a target without budget data")
+	}
+}
+
+func TestCompositeSLO(t *testing.T) {
+	adapter := synthetic.NewAdapter()
+	fixture := func(good, total float64) *synthetic.MetricFixture {
+		return &synthetic.MetricFixture{Windows: map[string]synthetic.WindowData{
+			"5m":  {Good: good, Total: total},
+			"1h":  {Good: good, Total: total},
+			"30d": {Good: good, Total: total},
+		}}
+	}
+	adapter.SetFixture("cart", fixture(1000, 1000))
+	adapter.SetFixture("payment", fixture(800, 1000))
+
+	component := func(id string) *slo.SLO {
+		return &slo.SLO{
+			Kind:     slo.KindSLO,
+			Metadata: slo.Metadata{ID: id},
+			Spec: slo.Spec{
+				Objective:        0.99,
+				ComplianceWindow: "30d",
+				SLI: slo.SLI{
+					Type:  "ratio",
+					Good:  slo.QueryRef{PrometheusQuery: id},
+					Total: slo.QueryRef{PrometheusQuery: id},
+				},
+			},
+		}
+	}
+	components := map[string]*slo.SLO{
+		"cart":    component("cart"),
+		"payment": component("payment"),
+	}
+
+	journey := &slo.SLO{
+		Kind:     slo.KindCompositeSLO,
+		Metadata: slo.Metadata{ID: "journey"},
+		Spec: slo.Spec{
+			Objective:        0.99,
+			ComplianceWindow: "30d",
+			Composite: &slo.Composite{
+				Aggregation: "weighted_ratio",
+				Components: []slo.CompositeComponent{
+					{SLOID: "cart", Weight: 3},
+					{SLOID: "payment"},
+				},
+			},
+			BurnPolicy: slo.BurnPolicy{
+				Rules: []slo.BurnRule{
+					{Name: "fast-burn", ShortWindow: "5m", LongWindow: "1h", Threshold: 4, Action: "BLOCK"},
+				},
+			},
+		},
+	}
+
+	evalResult, err := eval.NewEvaluator(adapter).EvaluateComposite(context.Background(), journey, components, time.Now())
+	if err != nil {
+		t.Fatalf("evaluation failed: %v", err)
+	}
+
+	// (3*1000 + 800) / (3*1000 + 1000) = 0.95 => 5% errors / 1% budget = 5x
+	if evalResult.SLI.Value < 0.9499 || evalResult.SLI.Value > 0.9501 {
+		t.Errorf("expected composite SLI=0.95, got %.4f", evalResult.SLI.Value)
+	}
+
+	gateResult := policy.NewEngine().Evaluate(journey, evalResult)
+	if gateResult.Decision != policy.DecisionBLOCK {
+		t.Errorf("expected BLOCK, got %s (reasons: %v)", gateResult.Decision, gateResult.Reasons)
+	}
+}
+
+func TestCalendarComplianceWindow(t *testing.T) {
+	// Halfway through February 2024 (29 days): 14d12h elapsed
+	now := time.Date(2024, 2, 15, 12, 0, 0, 0, time.UTC)
+
+	adapter := synthetic.NewAdapter()
+	adapter.SetFixture("billing", &synthetic.MetricFixture{Windows: map[string]synthetic.WindowData{
+		"5m":   {Good: 1000, Total: 1000},
+		"1h":   {Good: 1000, Total: 1000},
+		"348h": {Good: 9990, Total: 10000}, // burning exactly at budget rate
+	}})
+
+	sloSpec := &slo.SLO{
+		Metadata: slo.Metadata{ID: "billing"},
+		Spec: slo.Spec{
+			Objective:      0.999,
+			CalendarWindow: &slo.CalendarWindow{Calendar: "month"},
+			SLI: slo.SLI{
+				Type:  "ratio",
+				Good:  slo.QueryRef{PrometheusQuery: "billing"},
+				Total: slo.QueryRef{PrometheusQuery: "billing"},
+			},
+			BurnPolicy: slo.BurnPolicy{
+				Rules: []slo.BurnRule{
+					{Name: "fast-burn", ShortWindow: "5m", LongWindow: "1h", Threshold: 14, Action: "BLOCK"},
+				},
+			},
+		},
+	}
+
+	evalResult, err := eval.NewEvaluator(adapter).Evaluate(context.Background(), sloSpec, now)
+	if err != nil {
+		t.Fatalf("evaluation failed: %v", err)
+	}
+
+	if evalResult.ComplianceWindow != "348h" {
+		t.Errorf("expected elapsed compliance window 348h, got %s", evalResult.ComplianceWindow)
+	}
+
+	if evalResult.Period == nil {
+		t.Fatal("expected calendar period to be reported")
+	}
+
+	expectedFraction := 348.0 / (29 * 24)
+	if diff := evalResult.Period.ElapsedFraction - expectedFraction; diff > 0.0001 || diff < -0.0001 {
+		t.Errorf("expected elapsed fraction %.4f, got %.4f", expectedFraction, evalResult.Period.ElapsedFraction)
+	}
+
+	// Budget-rate errors consume the elapsed share of the period budget
+	if diff := evalResult.BudgetRemaining - (1 - expectedFraction); diff > 0.0001 || diff < -0.0001 {
+		t.Errorf("expected budget remaining %.4f, got %.4f", 1-expectedFraction, evalResult.BudgetRemaining)
+	}
+
+	// ... and would exhaust it exactly at period end
+	if evalResult.Period.ProjectedBudgetRemaining > 0.0001 {
+		t.Errorf("expected projected budget ~0 at period end, got %.4f", evalResult.Period.ProjectedBudgetRemaining)
+	}
+}
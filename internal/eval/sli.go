@@ -45,6 +45,21 @@ func ComputeBurnRate(errorRate, objective float64) float64 {
 	return errorRate / errorBudget
 }
 
+// WilsonLowerBound returns the lower bound of the Wilson score interval for a proportion
+// of hits out of n trials at the given two-sided confidence level
+func WilsonLowerBound(hits, n, confidence float64) float64 {
+	if n <= 0 {
+		return 0
+	}
+	p := math.Min(1, math.Max(0, hits/n))
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	z2 := z * z
+
+	centre := p + z2/(2*n)
+	margin := z * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	return math.Max(0, (centre-margin)/(1+z2/n))
+}
+
 // ComputeBudgetRemaining calculates remaining error budget
 // remaining_budget = 1 - (consumed_errors / allowed_errors)
 func ComputeBudgetRemaining(errorRate, objective float64) float64 {
@@ -66,3 +81,46 @@ func ComputeBudgetRemaining(errorRate, objective float64) float64 {
 	return remaining
 }
 
+// ComputeBudgetEvents counts the error budget of a compliance window in bad events:
+// allowed = (1 - objective) * total / elapsed_fraction, consumed = total - good.
+// elapsedFraction is 1 for rolling windows; calendar windows extrapolate the traffic
+// seen so far to the whole period.
+func ComputeBudgetEvents(good, total, objective, elapsedFraction float64) BudgetEvents {
+	if elapsedFraction <= 0 {
+		elapsedFraction = 1
+	}
+	allowed := math.Max(0, (1-objective)*total/elapsedFraction)
+	consumed := math.Max(0, total-good)
+	return BudgetEvents{
+		Allowed:   allowed,
+		Consumed:  consumed,
+		Remaining: math.Max(0, allowed-consumed),
+	}
+}
+
+// ComputeCalendarBudgetRemaining calculates remaining error budget for a calendar period.
+// The budget covers the whole period, so with uniform traffic only elapsedFraction of it
+// has been exposed to the observed error rate:
+// remaining_budget = 1 - (error_rate * elapsed_fraction / error_budget)
+func ComputeCalendarBudgetRemaining(errorRate, objective, elapsedFraction float64) float64 {
+	return ComputeBudgetRemaining(errorRate*elapsedFraction, objective)
+}
+
+// minSamples returns the minimum sample count of each window: gating.minDataPoints, but
+// never more samples than the window can hold at the adapter's counting resolution
+// (MaxSamples), e.g. a 5m window counted at a 5m range step holds one.
+func minSamples(minDataPoints int, windowMetrics map[string]WindowMetrics) map[string]int {
+	if minDataPoints <= 0 {
+		return nil
+	}
+
+	mins := make(map[string]int, len(windowMetrics))
+	for window, metrics := range windowMetrics {
+		n := minDataPoints
+		if metrics.MaxSamples != nil && n > *metrics.MaxSamples {
+			n = *metrics.MaxSamples
+		}
+		mins[window] = n
+	}
+	return mins
+}
@@ -163,3 +163,161 @@ func TestComputeBudgetRemaining(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeCalendarBudgetRemaining(t *testing.T) {
+	tests := []struct {
+		name                    string
+		errorRate               float64
+		elapsedFraction         float64
+		expectedBudgetRemaining float64
+	}{
+		{
+			name:                    "budget-rate errors halfway through period",
+			errorRate:               0.001,
+			elapsedFraction:         0.5,
+			expectedBudgetRemaining: 0.5,
+		},
+		{
+			name:                    "double budget-rate errors early in period",
+			errorRate:               0.002,
+			elapsedFraction:         0.1,
+			expectedBudgetRemaining: 0.8,
+		},
+		{
+			name:                    "period complete",
+			errorRate:               0.0005,
+			elapsedFraction:         1,
+			expectedBudgetRemaining: 0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining := ComputeCalendarBudgetRemaining(tt.errorRate, 0.999, tt.elapsedFraction)
+
+			if math.Abs(remaining-tt.expectedBudgetRemaining) > 0.0001 {
+				t.Errorf("expected budget remaining=%.4f, got %.4f",
+					tt.expectedBudgetRemaining, remaining)
+			}
+		})
+	}
+}
+
+func TestWilsonLowerBound(t *testing.T) {
+	tests := []struct {
+		name       string
+		hits       float64
+		n          float64
+		confidence float64
+		expected   float64
+	}{
+		{name: "one error in 40", hits: 1, n: 40, confidence: 0.95, expected: 0.00443},
+		{name: "no errors", hits: 0, n: 40, confidence: 0.95, expected: 0},
+		{name: "no trials", hits: 0, n: 0, confidence: 0.95, expected: 0},
+		{name: "large sample approaches the proportion", hits: 1000, n: 1000000, confidence: 0.95, expected: 0.00094},
+		{name: "higher confidence widens the interval", hits: 1, n: 40, confidence: 0.99, expected: 0.00294},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WilsonLowerBound(tt.hits, tt.n, tt.confidence)
+			if math.Abs(got-tt.expected) > 0.00005 {
+				t.Errorf("expected %.5f, got %.5f", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestComputeBudgetEvents(t *testing.T) {
+	tests := []struct {
+		name            string
+		good            float64
+		total           float64
+		objective       float64
+		elapsedFraction float64
+		expected        BudgetEvents
+	}{
+		{
+			name:            "rolling window exactly spent",
+			good:            99_900,
+			total:           100_000,
+			objective:       0.999,
+			elapsedFraction: 1,
+			expected:        BudgetEvents{Allowed: 100, Consumed: 100, Remaining: 0},
+		},
+		{
+			name:            "rolling window half spent",
+			good:            99_950,
+			total:           100_000,
+			objective:       0.999,
+			elapsedFraction: 1,
+			expected:        BudgetEvents{Allowed: 100, Consumed: 50, Remaining: 50},
+		},
+		{
+			name:            "overspent budget has nothing remaining",
+			good:            99_000,
+			total:           100_000,
+			objective:       0.999,
+			elapsedFraction: 1,
+			expected:        BudgetEvents{Allowed: 100, Consumed: 1000, Remaining: 0},
+		},
+		{
+			name:            "calendar period extrapolates traffic",
+			good:            24_980,
+			total:           25_000,
+			objective:       0.999,
+			elapsedFraction: 0.25,
+			expected:        BudgetEvents{Allowed: 100, Consumed: 20, Remaining: 80},
+		},
+		{
+			name:            "no traffic",
+			elapsedFraction: 1,
+			objective:       0.999,
+			expected:        BudgetEvents{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeBudgetEvents(tt.good, tt.total, tt.objective, tt.elapsedFraction)
+			if math.Abs(got.Allowed-tt.expected.Allowed) > 0.001 ||
+				math.Abs(got.Consumed-tt.expected.Consumed) > 0.001 ||
+				math.Abs(got.Remaining-tt.expected.Remaining) > 0.001 {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMinSamples(t *testing.T) {
+	one, twelve := 1, 12
+	windowMetrics := map[string]WindowMetrics{
+		"5m":  {Window: "5m", MaxSamples: &one}, // range strategy at a 5m step
+		"1h":  {Window: "1h", MaxSamples: &twelve},
+		"30d": {Window: "30d"},
+	}
+
+	tests := []struct {
+		name          string
+		minDataPoints int
+		expected      map[string]int
+	}{
+		{name: "disabled", minDataPoints: 0},
+		{name: "as given, capped", minDataPoints: 3, expected: map[string]int{"5m": 1, "1h": 3, "30d": 3}},
+		{name: "capped by each window", minDataPoints: 20, expected: map[string]int{"5m": 1, "1h": 12, "30d": 20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := minSamples(tt.minDataPoints, windowMetrics)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+			for window, n := range tt.expected {
+				if got[window] != n {
+					t.Errorf("window %s: expected %d, got %d", window, n, got[window])
+				}
+			}
+		})
+	}
+}
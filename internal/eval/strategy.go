@@ -0,0 +1,229 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/slo"
+	"golang.org/x/sync/errgroup"
+)
+
+// Strategy selects how window metrics are fetched from the adapter
+type Strategy string
+
+const (
+	// StrategyInstant issues one ratio query per window
+	StrategyInstant Strategy = "instant"
+	// StrategyRange issues one range query per series over the longest window at a
+	// coarse step and derives every window from the per-step samples locally
+	StrategyRange Strategy = "range"
+)
+
+// SetStrategy selects the query strategy. StrategyRange requires a RangeAdapter and a
+// positive step; windows that are not a whole multiple of the step are still queried
+// with instant queries.
+func (e *Evaluator) SetStrategy(strategy Strategy, step time.Duration) error {
+	switch strategy {
+	case StrategyInstant:
+	case StrategyRange:
+		if _, ok := e.adapter.(RangeAdapter); !ok {
+			return fmt.Errorf("range strategy requires a metrics adapter that supports range queries")
+		}
+		if step <= 0 {
+			return fmt.Errorf("range step must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown query strategy %q", strategy)
+	}
+
+	e.strategy = strategy
+	e.rangeStep = step
+	return nil
+}
+
+// queryWindows fetches metrics for every window of an SLO using the configured strategy.
+// Queries are issued concurrently. A failed query does not cancel the others: its error
+// is reported per window alongside the metrics of the windows that succeeded. The only
+// error returned is ctx's, or one for an adapter that cannot serve the SLI at all.
+func (e *Evaluator) queryWindows(ctx context.Context, sloSpec *slo.SLO, windows []string, at time.Time) (map[string]WindowMetrics, map[string]string, error) {
+	if err := e.checkAdapter(sloSpec); err != nil {
+		return nil, nil, err
+	}
+
+	windowMetrics := make(map[string]WindowMetrics, len(windows))
+	queryErrors := make(map[string]string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	record := func(windows []string, metrics map[string]WindowMetrics, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, window := range windows {
+			if err != nil {
+				queryErrors[window] = err.Error()
+			} else {
+				windowMetrics[window] = metrics[window]
+			}
+		}
+	}
+
+	instant := windows
+	if e.strategy == StrategyRange && sloSpec.Spec.SLI.Type != "time_slice" {
+		var derivable []string
+		derivable, instant = splitByStep(windows, e.rangeStep)
+		if len(derivable) > 0 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				derived, err := e.queryRangeWindows(ctx, sloSpec, derivable, at)
+				record(derivable, derived, err)
+			}()
+		}
+	}
+
+	for _, window := range instant {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			metrics, err := e.queryWindow(ctx, sloSpec, window, at)
+			record([]string{window}, map[string]WindowMetrics{window: metrics}, err)
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(queryErrors) == 0 {
+		queryErrors = nil
+	}
+	return windowMetrics, queryErrors, nil
+}
+
+// checkAdapter rejects SLIs the metrics adapter cannot serve before any query is issued
+func (e *Evaluator) checkAdapter(sloSpec *slo.SLO) error {
+	if sloSpec.Spec.SLI.Type != "time_slice" {
+		return nil
+	}
+	if sloSpec.Spec.SLI.TimeSlice == nil {
+		return fmt.Errorf("time_slice SLI requires sli.timeSlice")
+	}
+	if _, ok := e.adapter.(RangeAdapter); !ok {
+		return fmt.Errorf("metrics adapter does not support range queries required by time_slice SLIs")
+	}
+	return nil
+}
+
+// splitByStep separates windows that are whole multiples of step from the rest
+func splitByStep(windows []string, step time.Duration) (derivable, rest []string) {
+	for _, window := range windows {
+		d, err := slo.ParseDuration(window)
+		if err != nil || d < step || d%step != 0 {
+			rest = append(rest, window)
+			continue
+		}
+		derivable = append(derivable, window)
+	}
+	return derivable, rest
+}
+
+// queryRangeWindows fetches the good and total series once over the longest window and
+// sums the per-step samples that fall inside each window. Each sample summarises one
+// step, so queries should use increase() for window values to be event counts; the
+// SLI ratio is the same either way. Points are aligned to step boundaries, so a last
+// point within one step of the evaluation time is as fresh as the range query allows
+// and the windows are stamped with the evaluation time.
+func (e *Evaluator) queryRangeWindows(ctx context.Context, sloSpec *slo.SLO, windows []string, at time.Time) (map[string]WindowMetrics, error) {
+	rangeAdapter := e.adapter.(RangeAdapter)
+
+	durations := make(map[string]time.Duration, len(windows))
+	longest := windows[0]
+	for _, window := range windows {
+		d, _ := slo.ParseDuration(window)
+		durations[window] = d
+		if d > durations[longest] {
+			longest = window
+		}
+	}
+	step := slo.FormatDuration(e.rangeStep)
+
+	var good, total []Sample
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		good, err = rangeAdapter.QueryRange(gctx, sloSpec.Spec.SLI.Good.PrometheusQuery, longest, step, at, AggregateSum)
+		if err != nil {
+			return fmt.Errorf("range query good (window=%s, step=%s): %w", longest, step, err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		total, err = rangeAdapter.QueryRange(gctx, sloSpec.Spec.SLI.Total.PrometheusQuery, longest, step, at, AggregateSum)
+		if err != nil {
+			return fmt.Errorf("range query total (window=%s, step=%s): %w", longest, step, err)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Both series are summed up to the same end so their windows line up
+	var end time.Time
+	for _, sample := range append(append([]Sample(nil), good...), total...) {
+		if sample.Timestamp.After(end) {
+			end = sample.Timestamp
+		}
+	}
+
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	windowMetrics := make(map[string]WindowMetrics, len(windows))
+	for _, window := range windows {
+		metrics := ratioToWindowMetrics(window, RatioResult{
+			Window: window,
+			Good:   sumWindow(good, end, durations[window], e.rangeStep),
+			Total:  sumWindow(total, end, durations[window], e.rangeStep),
+		})
+		if metrics.DataTimestamp != nil && at.Sub(*metrics.DataTimestamp) < e.rangeStep {
+			metrics.DataTimestamp = &at
+		}
+		windowMetrics[window] = metrics
+	}
+
+	return windowMetrics, nil
+}
+
+// sumWindow sums the samples in (end-window, end]. The sample count is the number of
+// steps with data, out of the window's steps.
+func sumWindow(samples []Sample, end time.Time, window time.Duration, step time.Duration) SeriesValue {
+	start := end.Add(-window)
+
+	var sum float64
+	var latest *time.Time
+	count := 0
+	for i := range samples {
+		ts := samples[i].Timestamp
+		if ts.After(start) && !ts.After(end) {
+			sum += samples[i].Value
+			count++
+			if latest == nil || ts.After(*latest) {
+				latest = &samples[i].Timestamp
+			}
+		}
+	}
+
+	steps := int(window / step)
+	return SeriesValue{
+		Value:       sum,
+		SampleCount: &count,
+		MaxSamples:  &steps,
+		Timestamp:   latest,
+	}
+}
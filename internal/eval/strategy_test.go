@@ -0,0 +1,220 @@
+package eval
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/slo"
+)
+
+// seriesStub serves per-step counters for "good" and "total" from a function of the
+// step's age, answering both instant and range queries from the same data
+type seriesStub struct {
+	end   time.Time
+	step  time.Duration
+	value func(query string, age time.Duration) float64
+
+	mu           sync.Mutex
+	ratioQueries int
+	rangeQueries int
+}
+
+func (s *seriesStub) sum(query string, window time.Duration) float64 {
+	var total float64
+	for age := time.Duration(0); age < window; age += s.step {
+		total += s.value(query, age)
+	}
+	return total
+}
+
+func (s *seriesStub) QueryRatio(ctx context.Context, query RatioQuery) (RatioResult, error) {
+	s.mu.Lock()
+	s.ratioQueries++
+	s.mu.Unlock()
+
+	window, err := slo.ParseDuration(query.Window)
+	if err != nil {
+		return RatioResult{}, err
+	}
+	return RatioResult{
+		Window: query.Window,
+		Good:   SeriesValue{Value: s.sum(query.Good, window), Timestamp: &s.end},
+		Total:  SeriesValue{Value: s.sum(query.Total, window), Timestamp: &s.end},
+	}, nil
+}
+
+func (s *seriesStub) QueryRange(ctx context.Context, query string, window string, step string, end time.Time, aggregation SeriesAggregation) ([]Sample, error) {
+	s.mu.Lock()
+	s.rangeQueries++
+	s.mu.Unlock()
+
+	windowDur, err := slo.ParseDuration(window)
+	if err != nil {
+		return nil, err
+	}
+	stepDur, err := slo.ParseDuration(step)
+	if err != nil {
+		return nil, err
+	}
+	var samples []Sample
+	for age := windowDur - stepDur; age >= 0; age -= stepDur {
+		// Each sample summarises the stub's finer steps within it
+		var value float64
+		for sub := age; sub < age+stepDur; sub += s.step {
+			value += s.value(query, sub)
+		}
+		samples = append(samples, Sample{Timestamp: s.end.Add(-age), Value: value})
+	}
+	return samples, nil
+}
+
+func newSeriesStub() *seriesStub {
+	return &seriesStub{
+		end:  time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		step: 5 * time.Minute,
+		value: func(query string, age time.Duration) float64 {
+			if query == "total" {
+				return 100
+			}
+			// An incident in the last hour
+			if age < time.Hour {
+				return 80
+			}
+			return 99.9
+		},
+	}
+}
+
+func strategySLO() *slo.SLO {
+	return &slo.SLO{
+		Metadata: slo.Metadata{ID: "strategy"},
+		Spec: slo.Spec{
+			Objective:        0.99,
+			ComplianceWindow: "30d",
+			SLI: slo.SLI{
+				Type:  "ratio",
+				Good:  slo.QueryRef{PrometheusQuery: "good"},
+				Total: slo.QueryRef{PrometheusQuery: "total"},
+			},
+			BurnPolicy: slo.BurnPolicy{Rules: []slo.BurnRule{
+				{ShortWindow: "5m", LongWindow: "1h", Threshold: 14, Action: "BLOCK"},
+				{ShortWindow: "30m", LongWindow: "6h", Threshold: 6, Action: "WARN"},
+			}},
+		},
+	}
+}
+
+func TestRangeStrategy_MatchesInstant(t *testing.T) {
+	instantStub := newSeriesStub()
+	instant, err := NewEvaluator(instantStub).Evaluate(context.Background(), strategySLO(), instantStub.end)
+	if err != nil {
+		t.Fatalf("instant evaluation failed: %v", err)
+	}
+
+	rangeStub := newSeriesStub()
+	evaluator := NewEvaluator(rangeStub)
+	if err := evaluator.SetStrategy(StrategyRange, 5*time.Minute); err != nil {
+		t.Fatalf("SetStrategy failed: %v", err)
+	}
+	ranged, err := evaluator.Evaluate(context.Background(), strategySLO(), rangeStub.end)
+	if err != nil {
+		t.Fatalf("range evaluation failed: %v", err)
+	}
+
+	// 5 windows: 10 instant queries versus one range query per series
+	if instantStub.ratioQueries != 5 || instantStub.rangeQueries != 0 {
+		t.Errorf("instant strategy: expected 5 ratio queries, got %d ratio and %d range", instantStub.ratioQueries, instantStub.rangeQueries)
+	}
+	if rangeStub.ratioQueries != 0 || rangeStub.rangeQueries != 2 {
+		t.Errorf("range strategy: expected 2 range queries, got %d ratio and %d range", rangeStub.ratioQueries, rangeStub.rangeQueries)
+	}
+
+	if math.Abs(instant.SLI.Value-ranged.SLI.Value) > 0.0001 {
+		t.Errorf("SLI differs: instant=%f range=%f", instant.SLI.Value, ranged.SLI.Value)
+	}
+	for window, want := range instant.BurnRates {
+		got, ok := ranged.BurnRates[window]
+		if !ok || math.Abs(want.BurnRate-got.BurnRate) > 0.0001 {
+			t.Errorf("burn rate for %s differs: instant=%f range=%f", window, want.BurnRate, got.BurnRate)
+		}
+	}
+}
+
+func TestRangeStrategy_FreshMidStep(t *testing.T) {
+	tests := []struct {
+		name          string
+		sinceLastStep time.Duration
+		expectedStale bool
+	}{
+		{name: "on the step boundary", sinceLastStep: 0, expectedStale: false},
+		{name: "mid step", sinceLastStep: 3 * time.Minute, expectedStale: false},
+		{name: "a step behind", sinceLastStep: 7 * time.Minute, expectedStale: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newSeriesStub()
+			evaluator := NewEvaluator(stub)
+			if err := evaluator.SetStrategy(StrategyRange, 5*time.Minute); err != nil {
+				t.Fatalf("SetStrategy failed: %v", err)
+			}
+
+			sloSpec := strategySLO()
+			sloSpec.Spec.Gating.StalenessLimit = "120s"
+
+			// The stub's last point is on the step boundary before now
+			result, err := evaluator.Evaluate(context.Background(), sloSpec, stub.end.Add(tt.sinceLastStep))
+			if err != nil {
+				t.Fatalf("range evaluation failed: %v", err)
+			}
+			if result.IsStale != tt.expectedStale {
+				t.Errorf("expected stale=%v, got %v (freshness %v)", tt.expectedStale, result.IsStale, result.Freshness)
+			}
+		})
+	}
+}
+
+func TestRangeStrategy_FallsBackForUnalignedWindows(t *testing.T) {
+	stub := newSeriesStub()
+	evaluator := NewEvaluator(stub)
+	if err := evaluator.SetStrategy(StrategyRange, time.Hour); err != nil {
+		t.Fatalf("SetStrategy failed: %v", err)
+	}
+
+	metrics, queryErrors, err := evaluator.queryWindows(context.Background(), strategySLO(), []string{"5m", "30m", "6h", "30d"}, stub.end)
+	if err != nil || queryErrors != nil {
+		t.Fatalf("queryWindows failed: %v %v", err, queryErrors)
+	}
+
+	if len(metrics) != 4 {
+		t.Fatalf("expected 4 windows, got %d", len(metrics))
+	}
+	// 5m and 30m are shorter than the step and are queried instantly
+	if stub.ratioQueries != 2 || stub.rangeQueries != 2 {
+		t.Errorf("expected 2 ratio and 2 range queries, got %d and %d", stub.ratioQueries, stub.rangeQueries)
+	}
+	if metrics["6h"].SampleCount == nil || *metrics["6h"].SampleCount != 6 {
+		t.Errorf("expected 6 samples in the 6h window, got %v", metrics["6h"].SampleCount)
+	}
+	if metrics["6h"].MaxSamples == nil || *metrics["6h"].MaxSamples != 6 {
+		t.Errorf("expected at most 6 samples in the 6h window, got %v", metrics["6h"].MaxSamples)
+	}
+}
+
+func TestSetStrategy_Validation(t *testing.T) {
+	if err := NewEvaluator(instantOnly{}).SetStrategy(StrategyRange, 5*time.Minute); err == nil {
+		t.Error("expected error for adapter without range support")
+	}
+	if err := NewEvaluator(newSeriesStub()).SetStrategy(StrategyRange, 0); err == nil {
+		t.Error("expected error for zero step")
+	}
+	if err := NewEvaluator(newSeriesStub()).SetStrategy("batch", time.Minute); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+	if err := NewEvaluator(instantOnly{}).SetStrategy(StrategyInstant, 0); err != nil {
+		t.Errorf("expected instant strategy to need no range support, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package eval
+
+// ComputeTimeSlices counts good slices for a time_slice SLI.
+// Each sample is one slice; it is good when its value meets the target
+// under the comparison (>=, >, <=, <; default >=).
+// Returns good slices and total observed slices. Slices without data are not counted.
+func ComputeTimeSlices(samples []Sample, target float64, comparison string) (good, total float64) {
+	for _, sample := range samples {
+		total++
+		if sliceMeetsTarget(sample.Value, target, comparison) {
+			good++
+		}
+	}
+	return good, total
+}
+
+// sliceMeetsTarget reports whether a single slice value meets the target
+func sliceMeetsTarget(value, target float64, comparison string) bool {
+	switch comparison {
+	case ">":
+		return value > target
+	case "<=":
+		return value <= target
+	case "<":
+		return value < target
+	default:
+		return value >= target
+	}
+}
@@ -0,0 +1,158 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/slo"
+)
+
+func TestComputeTimeSlices(t *testing.T) {
+	samples := []Sample{
+		{Value: 1.0},
+		{Value: 0.99},
+		{Value: 0.5},
+		{Value: 0.95},
+	}
+
+	tests := []struct {
+		name          string
+		target        float64
+		comparison    string
+		expectedGood  float64
+		expectedTotal float64
+	}{
+		{
+			name:          "default comparison is >=",
+			target:        0.95,
+			expectedGood:  3,
+			expectedTotal: 4,
+		},
+		{
+			name:          "strictly greater",
+			target:        0.95,
+			comparison:    ">",
+			expectedGood:  2,
+			expectedTotal: 4,
+		},
+		{
+			name:          "less than or equal",
+			target:        0.95,
+			comparison:    "<=",
+			expectedGood:  2,
+			expectedTotal: 4,
+		},
+		{
+			name:          "strictly less",
+			target:        0.95,
+			comparison:    "<",
+			expectedGood:  1,
+			expectedTotal: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			good, total := ComputeTimeSlices(samples, tt.target, tt.comparison)
+			if good != tt.expectedGood || total != tt.expectedTotal {
+				t.Errorf("expected %v/%v good slices, got %v/%v",
+					tt.expectedGood, tt.expectedTotal, good, total)
+			}
+		})
+	}
+}
+
+// rangeStub returns a fixed slice series per window
+type rangeStub struct {
+	slices map[string][]float64
+}
+
+func (r *rangeStub) QueryRatio(ctx context.Context, query RatioQuery) (RatioResult, error) {
+	return RatioResult{}, fmt.Errorf("instant queries not supported")
+}
+
+func (r *rangeStub) QueryRange(ctx context.Context, query string, window string, step string, end time.Time, aggregation SeriesAggregation) ([]Sample, error) {
+	values, ok := r.slices[window]
+	if !ok {
+		return nil, fmt.Errorf("window not found: %s", window)
+	}
+	samples := make([]Sample, len(values))
+	for i, v := range values {
+		samples[i] = Sample{Timestamp: time.Now(), Value: v}
+	}
+	return samples, nil
+}
+
+func TestEvaluator_TimeSlice(t *testing.T) {
+	adapter := &rangeStub{slices: map[string][]float64{
+		"5m":  {1, 1, 0, 0, 1},
+		"1h":  {1, 1, 1, 1, 0, 1, 1, 1, 1, 1},
+		"30d": {1, 1, 1, 1, 1, 1, 1, 1, 1, 0},
+	}}
+
+	sloSpec := &slo.SLO{
+		Metadata: slo.Metadata{ID: "uptime"},
+		Spec: slo.Spec{
+			Objective:        0.9,
+			ComplianceWindow: "30d",
+			SLI: slo.SLI{
+				Type: "time_slice",
+				TimeSlice: &slo.TimeSlice{
+					Query:  slo.QueryRef{PrometheusQuery: "up"},
+					Slice:  "1m",
+					Target: 1,
+				},
+			},
+			BurnPolicy: slo.BurnPolicy{
+				Rules: []slo.BurnRule{
+					{Name: "fast", ShortWindow: "5m", LongWindow: "1h", Threshold: 2, Action: "BLOCK"},
+				},
+			},
+		},
+	}
+
+	result, err := NewEvaluator(adapter).Evaluate(context.Background(), sloSpec, time.Now())
+	if err != nil {
+		t.Fatalf("evaluation failed: %v", err)
+	}
+
+	if math.Abs(result.SLI.Value-0.9) > 0.0001 {
+		t.Errorf("expected compliance SLI=0.9, got %.4f", result.SLI.Value)
+	}
+
+	// 2 of 5 bad slices => error rate 0.4, burn 4x against a 10% budget
+	if br := result.BurnRates["5m"].BurnRate; math.Abs(br-4.0) > 0.0001 {
+		t.Errorf("expected 5m burn rate=4.0, got %.4f", br)
+	}
+
+	if math.Abs(result.BudgetRemaining) > 0.0001 {
+		t.Errorf("expected budget remaining=0, got %.4f", result.BudgetRemaining)
+	}
+}
+
+func TestEvaluator_TimeSlice_RequiresRangeAdapter(t *testing.T) {
+	sloSpec := &slo.SLO{
+		Spec: slo.Spec{
+			Objective:        0.9,
+			ComplianceWindow: "30d",
+			SLI: slo.SLI{
+				Type:      "time_slice",
+				TimeSlice: &slo.TimeSlice{Query: slo.QueryRef{PrometheusQuery: "up"}, Slice: "1m", Target: 1},
+			},
+		},
+	}
+
+	_, err := NewEvaluator(instantOnly{}).Evaluate(context.Background(), sloSpec, time.Now())
+	if err == nil {
+		t.Fatal("expected error for adapter without range support")
+	}
+}
+
+type instantOnly struct{}
+
+func (instantOnly) QueryRatio(ctx context.Context, query RatioQuery) (RatioResult, error) {
+	return RatioResult{Window: query.Window}, nil
+}
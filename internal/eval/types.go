@@ -1,6 +1,9 @@
 package eval
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // WindowMetrics represents metrics for a specific time window
 type WindowMetrics struct {
@@ -8,6 +11,62 @@ type WindowMetrics struct {
 	Good          float64
 	Total         float64
 	DataTimestamp *time.Time // Optional: for staleness checking
+	SampleCount   *int       // Optional: fewest samples behind either series, when the adapter reports it
+	MaxSamples    *int       // Optional: most samples SampleCount could be at the counting resolution
+}
+
+// RatioQuery asks an adapter for the good and total series of a ratio SLI over one window
+// ending at Time (the current time when zero). {{window}} in either query is substituted
+// with Window.
+type RatioQuery struct {
+	Good   string
+	Total  string
+	Window string
+	Time   time.Time
+}
+
+// SeriesValue is one side of a ratio query result
+type SeriesValue struct {
+	Value       float64
+	SampleCount *int       // Optional: number of samples behind Value
+	MaxSamples  *int       // Optional: most samples the window can hold at the counting resolution
+	Timestamp   *time.Time // Optional: time of the newest sample
+}
+
+// RatioResult holds both series of a ratio query
+type RatioResult struct {
+	Window string
+	Good   SeriesValue
+	Total  SeriesValue
+}
+
+// GroupRatio holds the good and total series of one group of label values
+type GroupRatio struct {
+	Labels map[string]string
+	Good   float64
+	Total  float64
+}
+
+// Contributor is a group of label values and its part in a window's bad events
+type Contributor struct {
+	Labels    map[string]string
+	Good      float64
+	Total     float64
+	BadEvents float64 // total - good
+	Share     float64 // of the window's bad events across all groups
+}
+
+// QueryStats reports how often an adapter answered a query without hitting its backend
+type QueryStats struct {
+	Hits    int64 // served from the cache or shared with an identical in-flight query
+	Misses  int64 // sent to the backend
+	Entries int   // results currently cached
+}
+
+// Sample is a single point of a range query result
+type Sample struct {
+	Timestamp time.Time
+	Value     float64
 }
 
 // SLIResult represents the computed SLI value
@@ -21,9 +80,40 @@ type SLIResult struct {
 // BurnRateResult represents burn rate computation for a window
 type BurnRateResult struct {
 	Window    string
-	BurnRate  float64
+	BurnRate  float64 // point estimate
 	SLI       float64
 	ErrorRate float64
+	Events    float64 // total events in the window
+	// Fewer events than gating.minEvents: the window cannot trigger rules
+	InsufficientEvents bool
+	// Optional: burn rate at the lower bound of the error rate's Wilson interval, when
+	// gating.confidence is set
+	BurnRateLowerBound *float64
+}
+
+// Freshness describes how recent a window's data is
+type Freshness string
+
+const (
+	FreshnessFresh   Freshness = "fresh"   // newest sample within gating.stalenessLimit
+	FreshnessStale   Freshness = "stale"   // newest sample older than gating.stalenessLimit
+	FreshnessUnknown Freshness = "unknown" // no data timestamp, e.g. an empty result
+)
+
+var freshnessRank = map[Freshness]int{FreshnessFresh: 0, FreshnessUnknown: 1, FreshnessStale: 2}
+
+// worse reports whether f is a worse freshness than other: stale, then unknown, then fresh
+func (f Freshness) worse(other Freshness) bool {
+	return freshnessRank[f] > freshnessRank[other]
+}
+
+// GatedBurnRate returns the burn rate rules are compared against: the confidence lower
+// bound when there is one, the point estimate otherwise
+func (b BurnRateResult) GatedBurnRate() float64 {
+	if b.BurnRateLowerBound != nil {
+		return *b.BurnRateLowerBound
+	}
+	return b.BurnRate
 }
 
 // EvaluationResult represents the complete evaluation of an SLO
@@ -33,6 +123,63 @@ type EvaluationResult struct {
 	BurnRates        map[string]BurnRateResult // keyed by window
 	BudgetRemaining  float64
 	InsufficientData bool
-	IsStale          bool
-	Timestamp        time.Time
+	// Windows with fewer samples than their minimum (see minSamples), keyed by window;
+	// they also set InsufficientData
+	InsufficientSamples map[string]int
+	MinSamples          map[string]int // minimum sample count of each insufficient window
+	IsStale             bool
+	Freshness           map[string]Freshness // keyed by window; windows whose query failed are absent
+	Timestamp           time.Time
+	Targets             []TargetResult // latency_distribution only; top-level fields mirror the worst target
+	ComplianceWindow    string         // window queried for the compliance SLI
+	Period              *CompliancePeriod
+	Forecast            *Forecast     // nil when the budget is unknown
+	BudgetEvents        *BudgetEvents // nil when the budget is unknown
+	// Top contributors to bad events per burning window, with sli.breakdownBy
+	Breakdowns      map[string][]Contributor
+	BreakdownErrors map[string]string // failed breakdown queries keyed by window
+	Backfilled      bool              // evaluated at a past time by a backfill
+	QueryErrors     map[string]string // failed queries keyed by window; those windows are missing from BurnRates
+	Error           string            // the evaluation failed outright; only SLOID and Timestamp are set
+}
+
+// UnknownFreshnessWindows returns the windows without a data timestamp, sorted
+func (r *EvaluationResult) UnknownFreshnessWindows() []string {
+	var windows []string
+	for window, freshness := range r.Freshness {
+		if freshness == FreshnessUnknown {
+			windows = append(windows, window)
+		}
+	}
+	sort.Strings(windows)
+	return windows
+}
+
+// Failed reports whether any query of the evaluation, or the evaluation itself, failed
+func (r *EvaluationResult) Failed() bool {
+	return r.Error != "" || len(r.QueryErrors) > 0
+}
+
+// BudgetEvents is the error budget of the compliance window counted in bad events
+// (total - good). For calendar windows Allowed covers the whole period.
+type BudgetEvents struct {
+	Allowed   float64
+	Consumed  float64
+	Remaining float64 // never negative
+}
+
+// CompliancePeriod describes the current period of a calendar-aligned compliance window
+type CompliancePeriod struct {
+	Start                    time.Time
+	End                      time.Time
+	ElapsedFraction          float64
+	ProjectedBudgetRemaining float64 // at period end if the current error rate continues
+}
+
+// TargetResult represents the evaluation of a single latency_distribution target
+type TargetResult struct {
+	Name        string
+	ThresholdMs int
+	Objective   float64
+	Result      *EvaluationResult
 }
@@ -0,0 +1,163 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed five-field cron expression: minute hour day-of-month month
+// day-of-week. Fields accept *, values, ranges (a-b), lists (a,b) and steps (*/n, a-b/n);
+// months and weekdays also accept three-letter names (JAN, MON). As in cron, when both
+// day fields are restricted a day matching either one matches.
+type cronSchedule struct {
+	minute     [60]bool
+	hour       [24]bool
+	dayOfMonth [32]bool
+	month      [13]bool
+	dayOfWeek  [7]bool
+	anyDOM     bool
+	anyDOW     bool
+}
+
+var (
+	monthNames   = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	weekdayNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+)
+
+// cronSearchLimit bounds the search for the next matching minute, so schedules that
+// never match (e.g. 30 FEB) end the search
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// parseCron parses a five-field cron expression
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields (minute hour day-of-month month day-of-week), got %d", len(fields))
+	}
+
+	s := &cronSchedule{anyDOM: fields[2] == "*", anyDOW: fields[4] == "*"}
+	if err := parseCronField(fields[0], 0, 59, nil, s.minute[:]); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if err := parseCronField(fields[1], 0, 23, nil, s.hour[:]); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if err := parseCronField(fields[2], 1, 31, nil, s.dayOfMonth[:]); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if err := parseCronField(fields[3], 1, 12, monthNames, s.month[:]); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+
+	// Day of week accepts 7 for Sunday
+	var dow [8]bool
+	if err := parseCronField(fields[4], 0, 7, weekdayNames, dow[:]); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	copy(s.dayOfWeek[:], dow[:7])
+	s.dayOfWeek[0] = s.dayOfWeek[0] || dow[7]
+
+	return s, nil
+}
+
+// parseCronField sets the values a field matches in set. names, when given, are the
+// names of the values starting at min.
+func parseCronField(field string, min, max int, names []string, set []bool) error {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = parseCronValue(bounds[0], min, max, names); err != nil {
+				return err
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = parseCronValue(bounds[1], min, max, names); err != nil {
+					return err
+				}
+			} else if step > 1 {
+				// a/n runs from a to the end of the range
+				hi = max
+			}
+			if hi < lo {
+				return fmt.Errorf("invalid range %q", rangePart)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return nil
+}
+
+// parseCronValue parses a number or name within [min, max]
+func parseCronValue(value string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(value, name) {
+			return min + i, nil
+		}
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", n, min, max)
+	}
+	return n, nil
+}
+
+// next returns the first matching minute strictly after t, in loc, or the zero time
+// when none comes within cronSearchLimit
+func (s *cronSchedule) next(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		year, month, day := t.Date()
+		var next time.Time
+		switch {
+		case !s.month[month]:
+			next = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			next = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case !s.hour[t.Hour()]:
+			next = time.Date(year, month, day, t.Hour()+1, 0, 0, 0, loc)
+		case !s.minute[t.Minute()]:
+			next = t.Add(time.Minute)
+		default:
+			return t
+		}
+
+		// Daylight saving transitions can map a local time back; always move forward
+		if !next.After(t) {
+			next = t.Add(time.Minute)
+		}
+		t = next
+	}
+	return time.Time{}
+}
+
+// dayMatches reports whether t's day matches the day-of-month and day-of-week fields
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dayOfMonth[t.Day()]
+	dow := s.dayOfWeek[t.Weekday()]
+	if !s.anyDOM && !s.anyDOW {
+		return dom || dow
+	}
+	return dom && dow
+}
@@ -2,70 +2,288 @@ package policy
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/samijaber1/aegis-slo/internal/eval"
 	"github.com/samijaber1/aegis-slo/internal/slo"
 )
 
+// reasonChecksPassed is the only reason of an ALLOW decision nothing else explains
+const reasonChecksPassed = "all burn rate checks passed"
+
 // Engine evaluates burn policies and produces gate decisions
-type Engine struct{}
+type Engine struct {
+	queryErrorDecision Decision
+	freezeCalendar     *FreezeCalendar
+
+	mu         sync.Mutex
+	ruleStates map[string]map[ruleKey]RuleState // SLO ID -> hysteresis state of its rules
+}
 
 // NewEngine creates a new policy engine
+// Failed evaluations and query errors yield WARN unless configured otherwise.
 func NewEngine() *Engine {
-	return &Engine{}
+	return &Engine{
+		queryErrorDecision: DecisionWARN,
+		ruleStates:         make(map[string]map[ruleKey]RuleState),
+	}
+}
+
+// Clone returns an engine with the same configuration and no rule state, for
+// evaluations that must not affect the live hysteresis state, such as backfills
+func (e *Engine) Clone() *Engine {
+	clone := NewEngine()
+	clone.queryErrorDecision = e.queryErrorDecision
+	clone.freezeCalendar = e.freezeCalendar
+	return clone
+}
+
+// SetQueryErrorDecision sets the decision for evaluations whose queries failed
+func (e *Engine) SetQueryErrorDecision(decision Decision) error {
+	if severity(decision) < 0 {
+		return fmt.Errorf("invalid decision %q", decision)
+	}
+	e.queryErrorDecision = decision
+	return nil
+}
+
+// SetFreezeCalendar sets the change freezes applied to every decision; nil disables them
+func (e *Engine) SetFreezeCalendar(calendar *FreezeCalendar) {
+	e.freezeCalendar = calendar
+}
+
+// FreezeCalendar returns the change freezes applied to every decision, if any
+func (e *Engine) FreezeCalendar() *FreezeCalendar {
+	return e.freezeCalendar
 }
 
-// Evaluate applies burn policies and gating modifiers to produce a decision
+// Evaluate applies burn policies, gating modifiers and the change freezes active at the
+// evaluation time to produce a decision
 func (e *Engine) Evaluate(sloSpec *slo.SLO, evalResult *eval.EvaluationResult) *GateResult {
+	return e.ApplyFreezes(sloSpec, e.EvaluateHealth(sloSpec, evalResult), evalResult.Timestamp)
+}
+
+// ApplyFreezes returns result with the change freezes active at the given time applied,
+// whatever the SLO health. result is returned as is when no freeze is active.
+func (e *Engine) ApplyFreezes(sloSpec *slo.SLO, result *GateResult, at time.Time) *GateResult {
+	if e.freezeCalendar == nil {
+		return result
+	}
+	windows := e.freezeCalendar.Active(sloSpec.Metadata.Service, sloSpec.Spec.Environment, at)
+	if len(windows) == 0 {
+		return result
+	}
+
+	frozen := *result
+	frozen.Freezes = make([]string, 0, len(windows))
+	frozen.Reasons = make([]string, 0, len(windows)+len(result.Reasons))
+	for _, window := range windows {
+		frozen.Decision = escalate(frozen.Decision, Decision(window.Freeze.Action))
+		frozen.Freezes = append(frozen.Freezes, window.Freeze.Name)
+		frozen.Reasons = append(frozen.Reasons, freezeReason(window))
+	}
+	if !(result.Decision == DecisionALLOW && slices.Equal(result.Reasons, []string{reasonChecksPassed})) {
+		frozen.Reasons = append(frozen.Reasons, result.Reasons...)
+	}
+	return &frozen
+}
+
+// EvaluateHealth applies burn policies and gating modifiers to produce a decision,
+// leaving out change freezes
+func (e *Engine) EvaluateHealth(sloSpec *slo.SLO, evalResult *eval.EvaluationResult) *GateResult {
 	result := &GateResult{
 		Decision:     DecisionALLOW,
 		RuleResults:  []RuleResult{},
 		Reasons:      []string{},
 		IsStale:      evalResult.IsStale,
 		HasNoTraffic: evalResult.InsufficientData,
+		QueryFailed:  evalResult.Failed(),
+	}
+
+	gating := sloSpec.Spec.Gating
+	queryErrorDecision := gatingAction(gating.OnQueryError, e.queryErrorDecision)
+
+	// A failed evaluation has no burn rates to judge
+	if evalResult.Error != "" {
+		result.Decision = escalate(result.Decision, queryErrorDecision)
+		result.Reasons = append(result.Reasons, fmt.Sprintf("evaluation failed: %s", evalResult.Error))
+		return result
 	}
 
-	// Apply gating modifiers first
-	if evalResult.IsStale {
-		result.Decision = DecisionWARN
+	// Failed queries leave their windows unknown
+	if len(evalResult.QueryErrors) > 0 {
+		result.Decision = escalate(result.Decision, queryErrorDecision)
+		windows := make([]string, 0, len(evalResult.QueryErrors))
+		for window := range evalResult.QueryErrors {
+			windows = append(windows, window)
+		}
+		sort.Strings(windows)
+		for _, window := range windows {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("query failed for window %s: %s", window, evalResult.QueryErrors[window]))
+		}
+	}
+
+	// Apply gating modifiers first; stale or insufficient data set to ALLOW is not reported
+	if action := gatingAction(gating.OnStale, DecisionWARN); evalResult.IsStale && action != DecisionALLOW {
+		result.Decision = escalate(result.Decision, action)
 		result.Reasons = append(result.Reasons, "data is stale")
 	}
 
-	if evalResult.InsufficientData {
-		result.Decision = DecisionWARN
-		result.Reasons = append(result.Reasons, "insufficient data (zero traffic)")
+	if action := Decision(gating.OnUnknownFreshness); action != "" && action != DecisionALLOW {
+		if windows := evalResult.UnknownFreshnessWindows(); len(windows) > 0 {
+			result.Decision = escalate(result.Decision, action)
+			result.Reasons = append(result.Reasons, fmt.Sprintf("freshness unknown (no data timestamp) for windows %s", strings.Join(windows, ", ")))
+		}
 	}
 
-	// Evaluate burn policy rules
-	for _, rule := range sloSpec.Spec.BurnPolicy.Rules {
-		ruleResult := e.evaluateRule(rule, evalResult)
-		result.RuleResults = append(result.RuleResults, ruleResult)
-
-		if ruleResult.Triggered {
-			// Aggregate decisions: BLOCK > WARN > ALLOW
-			if ruleResult.Action == DecisionBLOCK {
-				result.Decision = DecisionBLOCK
-			} else if ruleResult.Action == DecisionWARN && result.Decision != DecisionBLOCK {
-				result.Decision = DecisionWARN
-			}
+	if action := gatingAction(gating.OnNoTraffic, DecisionWARN); evalResult.InsufficientData && action != DecisionALLOW {
+		result.Decision = escalate(result.Decision, action)
+		if reasons := insufficientDataReasons(sloSpec, evalResult); len(reasons) > 0 {
+			result.Reasons = append(result.Reasons, reasons...)
+		} else {
+			result.Reasons = append(result.Reasons, "insufficient data (zero traffic)")
+		}
+	}
 
-			result.Reasons = append(result.Reasons, ruleResult.Reason)
+	// Evaluate burn and budget rules, once per target for latency distributions. Rule
+	// states are replaced as a whole so that those of removed rules are dropped.
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	states := e.ruleStates[sloSpec.Metadata.ID]
+	nextStates := make(map[ruleKey]RuleState)
+	if len(evalResult.Targets) > 0 {
+		for _, target := range evalResult.Targets {
+			for _, ruleResult := range e.evaluateRules(sloSpec, target.Name, target.Result, states, nextStates) {
+				ruleResult.Target = target.Name
+				if ruleResult.Triggered || ruleResult.Pending {
+					ruleResult.Reason = fmt.Sprintf("target %s (%.4g%% under %dms): %s",
+						target.Name, target.Objective*100, target.ThresholdMs, ruleResult.Reason)
+				}
+				applyRuleResult(result, ruleResult)
+			}
+		}
+	} else {
+		for _, ruleResult := range e.evaluateRules(sloSpec, "", evalResult, states, nextStates) {
+			applyRuleResult(result, ruleResult)
 		}
 	}
+	if len(nextStates) > 0 {
+		e.ruleStates[sloSpec.Metadata.ID] = nextStates
+	} else {
+		delete(e.ruleStates, sloSpec.Metadata.ID)
+	}
 
 	// If no specific reasons but decision is ALLOW, add positive reason
 	if result.Decision == DecisionALLOW && len(result.Reasons) == 0 {
-		result.Reasons = append(result.Reasons, "all burn rate checks passed")
+		result.Reasons = append(result.Reasons, reasonChecksPassed)
 	}
 
 	return result
 }
 
+// evaluateRules evaluates the burn rules, then the budget rules, of a burn policy. The
+// hysteresis of burn rules advances from states, the rule states of the previous
+// evaluation, into nextStates.
+func (e *Engine) evaluateRules(sloSpec *slo.SLO, target string, evalResult *eval.EvaluationResult, states, nextStates map[ruleKey]RuleState) []RuleResult {
+	policy := sloSpec.Spec.BurnPolicy
+	results := make([]RuleResult, 0, len(policy.Rules)+len(policy.BudgetRules))
+	for _, rule := range policy.Rules {
+		ruleResult := e.evaluateRule(rule, evalResult)
+		if rule.HasHysteresis() {
+			key := ruleKey{target: target, rule: rule.Name}
+			state, ok := states[key]
+			if !ok {
+				state = RuleState{SLOID: sloSpec.Metadata.ID, Target: target, Rule: rule.Name}
+			}
+			applyHysteresis(rule, evalResult, &ruleResult, &state)
+			if !state.idle() {
+				nextStates[key] = state
+			}
+		}
+		results = append(results, ruleResult)
+	}
+	for _, rule := range policy.BudgetRules {
+		results = append(results, e.evaluateBudgetRule(rule, evalResult))
+	}
+	return results
+}
+
+// gatingAction returns the action configured for a gating modifier, or def when unset
+func gatingAction(action string, def Decision) Decision {
+	if action == "" {
+		return def
+	}
+	return Decision(action)
+}
+
+// freezeReason describes an active freeze window
+func freezeReason(window FreezeWindow) string {
+	reason := fmt.Sprintf("change freeze %s until %s", window.Freeze.Name,
+		window.End.In(window.Freeze.location).Format(time.RFC3339))
+	if window.Freeze.Reason != "" {
+		reason += ": " + window.Freeze.Reason
+	}
+	return reason
+}
+
+// applyRuleResult records a rule result and aggregates its action into the decision
+func applyRuleResult(result *GateResult, ruleResult RuleResult) {
+	result.RuleResults = append(result.RuleResults, ruleResult)
+
+	if ruleResult.Triggered {
+		result.Decision = escalate(result.Decision, ruleResult.Action)
+		result.Reasons = append(result.Reasons, ruleResult.Reason)
+	} else if ruleResult.Pending {
+		result.Reasons = append(result.Reasons, ruleResult.Reason)
+	}
+}
+
+// insufficientDataReasons lists the windows below gating.minDataPoints, without traffic
+// or below gating.minEvents
+func insufficientDataReasons(sloSpec *slo.SLO, evalResult *eval.EvaluationResult) []string {
+	var reasons []string
+
+	windows := make([]string, 0, len(evalResult.InsufficientSamples))
+	for window := range evalResult.InsufficientSamples {
+		windows = append(windows, window)
+	}
+	sort.Strings(windows)
+	for _, window := range windows {
+		reasons = append(reasons, fmt.Sprintf("insufficient data for window %s: %d samples, minimum %d",
+			window, evalResult.InsufficientSamples[window], evalResult.MinSamples[window]))
+	}
+
+	windows = windows[:0]
+	for window, br := range evalResult.BurnRates {
+		if br.Events == 0 || br.InsufficientEvents {
+			windows = append(windows, window)
+		}
+	}
+	sort.Strings(windows)
+	for _, window := range windows {
+		if events := evalResult.BurnRates[window].Events; events == 0 {
+			reasons = append(reasons, fmt.Sprintf("insufficient data for window %s: zero traffic", window))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("insufficient events for window %s: %.0f events, minimum %.0f",
+				window, events, sloSpec.Spec.Gating.MinEvents))
+		}
+	}
+
+	return reasons
+}
+
 // evaluateRule evaluates a single burn rate rule
-// Rule triggers if: burn_short >= threshold AND burn_long >= threshold
+// Rule triggers if: burn_short >= threshold AND burn_long >= threshold. When the burn
+// rates carry a confidence lower bound, the bounds are compared instead, and windows
+// with too few events never trigger.
 func (e *Engine) evaluateRule(rule slo.BurnRule, evalResult *eval.EvaluationResult) RuleResult {
 	ruleResult := RuleResult{
 		RuleName: rule.Name,
+		Kind:     RuleKindBurnRate,
 		Action:   Decision(rule.Action),
 	}
 
@@ -83,19 +301,88 @@ func (e *Engine) evaluateRule(rule slo.BurnRule, evalResult *eval.EvaluationResu
 	ruleResult.LongBurnRate = longBurn.BurnRate
 	ruleResult.Threshold = rule.Threshold
 
+	if shortBurn.InsufficientEvents || longBurn.InsufficientEvents {
+		ruleResult.Triggered = false
+		ruleResult.Reason = fmt.Sprintf("rule %s: insufficient events", rule.Name)
+		return ruleResult
+	}
+
 	// Check if both windows exceed threshold
-	if shortBurn.BurnRate >= rule.Threshold && longBurn.BurnRate >= rule.Threshold {
+	if shortBurn.GatedBurnRate() >= rule.Threshold && longBurn.GatedBurnRate() >= rule.Threshold {
 		ruleResult.Triggered = true
 		ruleResult.Reason = fmt.Sprintf(
-			"rule %s triggered: short=%.2fx, long=%.2fx (threshold=%.2fx)",
+			"rule %s triggered: short=%s, long=%s (threshold=%.2fx)",
 			rule.Name,
-			shortBurn.BurnRate,
-			longBurn.BurnRate,
+			formatBurnRate(shortBurn),
+			formatBurnRate(longBurn),
 			rule.Threshold,
 		)
+		if top := evalResult.Breakdowns[rule.LongWindow]; len(top) > 0 {
+			ruleResult.Reason += fmt.Sprintf("; top contributor: %s (%.0f%% of bad events)",
+				eval.FormatLabels(top[0].Labels), top[0].Share*100)
+		}
 	} else {
 		ruleResult.Triggered = false
 	}
 
 	return ruleResult
 }
+
+// evaluateBudgetRule evaluates a single budget rule
+// Rule triggers if: budget remaining < threshold. The budget is unknown, and the rule
+// does not trigger, when the compliance window query failed.
+func (e *Engine) evaluateBudgetRule(rule slo.BudgetRule, evalResult *eval.EvaluationResult) RuleResult {
+	ruleResult := RuleResult{
+		RuleName:        rule.Name,
+		Kind:            RuleKindBudget,
+		Action:          Decision(rule.Action),
+		BudgetRemaining: evalResult.BudgetRemaining,
+		Threshold:       rule.Threshold,
+	}
+
+	if _, failed := evalResult.QueryErrors[evalResult.ComplianceWindow]; failed {
+		ruleResult.Reason = fmt.Sprintf("rule %s: budget unknown", rule.Name)
+		return ruleResult
+	}
+
+	if evalResult.BudgetRemaining < rule.Threshold {
+		ruleResult.Triggered = true
+		ruleResult.Reason = fmt.Sprintf(
+			"rule %s triggered: budget remaining %.1f%% < %.1f%%",
+			rule.Name,
+			evalResult.BudgetRemaining*100,
+			rule.Threshold*100,
+		)
+	}
+
+	return ruleResult
+}
+
+// formatBurnRate formats a burn rate with its lower bound when there is one
+func formatBurnRate(br eval.BurnRateResult) string {
+	if br.BurnRateLowerBound != nil {
+		return fmt.Sprintf("%.2fx (lower bound %.2fx)", br.BurnRate, *br.BurnRateLowerBound)
+	}
+	return fmt.Sprintf("%.2fx", br.BurnRate)
+}
+
+// escalate aggregates decisions: BLOCK > WARN > ALLOW
+func escalate(current, decision Decision) Decision {
+	if severity(decision) > severity(current) {
+		return decision
+	}
+	return current
+}
+
+// severity orders decisions, returning -1 for unknown ones
+func severity(decision Decision) int {
+	switch decision {
+	case DecisionALLOW:
+		return 0
+	case DecisionWARN:
+		return 1
+	case DecisionBLOCK:
+		return 2
+	}
+	return -1
+}
@@ -1,6 +1,7 @@
 package policy
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/samijaber1/aegis-slo/internal/eval"
@@ -78,6 +79,27 @@ func TestEngine_Evaluate(t *testing.T) {
 			sloSpec:          createTestSLO(),
 			expectedDecision: DecisionWARN,
 		},
+		{
+			name: "query error - warn",
+			evalResult: &eval.EvaluationResult{
+				SLOID:       "test",
+				QueryErrors: map[string]string{"1h": "http status 503"},
+				BurnRates: map[string]eval.BurnRateResult{
+					"5m": {BurnRate: 1.0},
+				},
+			},
+			sloSpec:          createTestSLO(),
+			expectedDecision: DecisionWARN,
+		},
+		{
+			name: "evaluation failed - warn",
+			evalResult: &eval.EvaluationResult{
+				SLOID: "test",
+				Error: "component SLO not found: api",
+			},
+			sloSpec:          createTestSLO(),
+			expectedDecision: DecisionWARN,
+		},
 		{
 			name: "stale + fast burn - block takes precedence",
 			evalResult: &eval.EvaluationResult{
@@ -107,6 +129,63 @@ func TestEngine_Evaluate(t *testing.T) {
 	}
 }
 
+func TestEngine_InsufficientDataReasons(t *testing.T) {
+	sloSpec := createTestSLO()
+	sloSpec.Spec.Gating.MinEvents = 100
+
+	tests := []struct {
+		name       string
+		evalResult *eval.EvaluationResult
+		expected   []string
+	}{
+		{
+			name: "zero traffic alongside a sample shortfall",
+			evalResult: &eval.EvaluationResult{
+				InsufficientData:    true,
+				InsufficientSamples: map[string]int{"1h": 30},
+				MinSamples:          map[string]int{"1h": 36},
+				BurnRates: map[string]eval.BurnRateResult{
+					"5m": {Events: 0},
+					"1h": {Events: 500},
+				},
+			},
+			expected: []string{
+				"insufficient data for window 1h: 30 samples, minimum 36",
+				"insufficient data for window 5m: zero traffic",
+			},
+		},
+		{
+			name: "zero traffic alongside too few events",
+			evalResult: &eval.EvaluationResult{
+				InsufficientData: true,
+				BurnRates: map[string]eval.BurnRateResult{
+					"5m": {Events: 0, InsufficientEvents: true},
+					"1h": {Events: 40, InsufficientEvents: true},
+				},
+			},
+			expected: []string{
+				"insufficient events for window 1h: 40 events, minimum 100",
+				"insufficient data for window 5m: zero traffic",
+			},
+		},
+		{
+			name:       "no window data",
+			evalResult: &eval.EvaluationResult{InsufficientData: true},
+			expected:   []string{"insufficient data (zero traffic)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.evalResult.SLOID = "test-slo"
+			result := NewEngine().Evaluate(sloSpec, tt.evalResult)
+			if !slices.Equal(result.Reasons, tt.expected) {
+				t.Errorf("expected reasons %v, got %v", tt.expected, result.Reasons)
+			}
+		})
+	}
+}
+
 func createTestSLO() *slo.SLO {
 	return &slo.SLO{
 		Metadata: slo.Metadata{
@@ -128,3 +207,172 @@ func createTestSLO() *slo.SLO {
 		},
 	}
 }
+
+func TestEngine_UnknownFreshness(t *testing.T) {
+	engine := NewEngine()
+	evalResult := &eval.EvaluationResult{
+		SLOID: "test",
+		BurnRates: map[string]eval.BurnRateResult{
+			"5m": {BurnRate: 1.0},
+			"1h": {BurnRate: 1.0},
+		},
+		Freshness: map[string]eval.Freshness{
+			"5m": eval.FreshnessUnknown,
+			"1h": eval.FreshnessFresh,
+		},
+	}
+
+	tests := []struct {
+		name             string
+		action           string
+		expectedDecision Decision
+	}{
+		{name: "default treats unknown as fresh", action: "", expectedDecision: DecisionALLOW},
+		{name: "warn", action: "WARN", expectedDecision: DecisionWARN},
+		{name: "block", action: "BLOCK", expectedDecision: DecisionBLOCK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sloSpec := createTestSLO()
+			sloSpec.Spec.Gating.OnUnknownFreshness = tt.action
+
+			result := engine.Evaluate(sloSpec, evalResult)
+			if result.Decision != tt.expectedDecision {
+				t.Errorf("expected %s, got %s (reasons: %v)", tt.expectedDecision, result.Decision, result.Reasons)
+			}
+			if tt.action != "" && result.Reasons[0] != "freshness unknown (no data timestamp) for windows 5m" {
+				t.Errorf("unexpected reasons: %v", result.Reasons)
+			}
+		})
+	}
+}
+
+func TestEngine_QueryErrorDecision(t *testing.T) {
+	engine := NewEngine()
+	if err := engine.SetQueryErrorDecision(DecisionBLOCK); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := engine.SetQueryErrorDecision("DENY"); err == nil {
+		t.Error("expected error for unknown decision")
+	}
+
+	result := engine.Evaluate(createTestSLO(), &eval.EvaluationResult{
+		SLOID:       "test",
+		QueryErrors: map[string]string{"5m": "timeout", "1h": "timeout"},
+	})
+
+	if result.Decision != DecisionBLOCK || !result.QueryFailed {
+		t.Errorf("expected BLOCK with QueryFailed, got %s (queryFailed=%v)", result.Decision, result.QueryFailed)
+	}
+	if len(result.Reasons) < 2 || result.Reasons[0] != "query failed for window 1h: timeout" {
+		t.Errorf("expected one reason per failed window in window order, got %v", result.Reasons)
+	}
+}
+
+func TestEngine_BudgetRules(t *testing.T) {
+	engine := NewEngine()
+	healthy := map[string]eval.BurnRateResult{
+		"5m": {BurnRate: 1.0},
+		"1h": {BurnRate: 1.0},
+	}
+
+	tests := []struct {
+		name             string
+		evalResult       *eval.EvaluationResult
+		expectedDecision Decision
+		expectedReason   string
+	}{
+		{
+			name:             "budget above thresholds",
+			evalResult:       &eval.EvaluationResult{ComplianceWindow: "30d", BudgetRemaining: 0.5, BurnRates: healthy},
+			expectedDecision: DecisionALLOW,
+		},
+		{
+			name:             "warn below 25%",
+			evalResult:       &eval.EvaluationResult{ComplianceWindow: "30d", BudgetRemaining: 0.2, BurnRates: healthy},
+			expectedDecision: DecisionWARN,
+			expectedReason:   "rule budget-low triggered: budget remaining 20.0% < 25.0%",
+		},
+		{
+			name:             "freeze below 10% regardless of burn",
+			evalResult:       &eval.EvaluationResult{ComplianceWindow: "30d", BudgetRemaining: 0.05, BurnRates: healthy},
+			expectedDecision: DecisionBLOCK,
+			expectedReason:   "rule budget-freeze triggered: budget remaining 5.0% < 10.0%",
+		},
+		{
+			name: "unknown budget does not trigger",
+			evalResult: &eval.EvaluationResult{
+				ComplianceWindow: "30d", BurnRates: healthy, QueryErrors: map[string]string{"30d": "timeout"},
+			},
+			expectedDecision: DecisionWARN,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sloSpec := createTestSLO()
+			sloSpec.Spec.BurnPolicy.BudgetRules = []slo.BudgetRule{
+				{Name: "budget-low", Threshold: 0.25, Action: "WARN"},
+				{Name: "budget-freeze", Threshold: 0.1, Action: "BLOCK"},
+			}
+
+			result := engine.Evaluate(sloSpec, tt.evalResult)
+			if result.Decision != tt.expectedDecision {
+				t.Errorf("expected %s, got %s (reasons: %v)", tt.expectedDecision, result.Decision, result.Reasons)
+			}
+			if tt.expectedReason != "" && !slices.Contains(result.Reasons, tt.expectedReason) {
+				t.Errorf("expected reason %q, got %v", tt.expectedReason, result.Reasons)
+			}
+
+			if len(result.RuleResults) != 3 {
+				t.Fatalf("expected 1 burn and 2 budget rule results, got %+v", result.RuleResults)
+			}
+			for _, rr := range result.RuleResults[1:] {
+				if rr.Kind != RuleKindBudget || rr.BudgetRemaining != tt.evalResult.BudgetRemaining {
+					t.Errorf("unexpected budget rule result %+v", rr)
+				}
+			}
+		})
+	}
+}
+
+func TestEngine_GatingActions(t *testing.T) {
+	tests := []struct {
+		name             string
+		gating           slo.Gating
+		evalResult       eval.EvaluationResult
+		expectedDecision Decision
+		expectedReasons  int
+	}{
+		{name: "stale default", evalResult: eval.EvaluationResult{IsStale: true}, expectedDecision: DecisionWARN, expectedReasons: 1},
+		{name: "stale blocks", gating: slo.Gating{OnStale: "BLOCK"}, evalResult: eval.EvaluationResult{IsStale: true}, expectedDecision: DecisionBLOCK, expectedReasons: 1},
+		{name: "no traffic default", evalResult: eval.EvaluationResult{InsufficientData: true}, expectedDecision: DecisionWARN, expectedReasons: 2}, // one per window,
+		{name: "no traffic allowed", gating: slo.Gating{OnNoTraffic: "ALLOW"}, evalResult: eval.EvaluationResult{InsufficientData: true}, expectedDecision: DecisionALLOW, expectedReasons: 1},
+		{name: "query error default", evalResult: eval.EvaluationResult{QueryErrors: map[string]string{"5m": "timeout"}}, expectedDecision: DecisionWARN, expectedReasons: 1},
+		{name: "query error blocks", gating: slo.Gating{OnQueryError: "BLOCK"}, evalResult: eval.EvaluationResult{QueryErrors: map[string]string{"5m": "timeout"}}, expectedDecision: DecisionBLOCK, expectedReasons: 1},
+		{name: "failed evaluation allowed", gating: slo.Gating{OnQueryError: "ALLOW"}, evalResult: eval.EvaluationResult{Error: "adapter unavailable"}, expectedDecision: DecisionALLOW, expectedReasons: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sloSpec := createTestSLO()
+			sloSpec.Spec.Gating = tt.gating
+
+			evalResult := tt.evalResult
+			evalResult.SLOID = "test-slo"
+			evalResult.BurnRates = map[string]eval.BurnRateResult{
+				"5m": {BurnRate: 1.0},
+				"1h": {BurnRate: 1.0},
+			}
+
+			result := NewEngine().Evaluate(sloSpec, &evalResult)
+			if result.Decision != tt.expectedDecision {
+				t.Errorf("expected %s, got %s (reasons: %v)", tt.expectedDecision, result.Decision, result.Reasons)
+			}
+			if len(result.Reasons) != tt.expectedReasons {
+				t.Errorf("expected %d reasons, got %v", tt.expectedReasons, result.Reasons)
+			}
+		})
+	}
+}
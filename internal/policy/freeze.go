@@ -0,0 +1,242 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/samijaber1/aegis-slo/internal/slo"
+)
+
+// maxFreezeOccurrences bounds the occurrences of a recurring freeze listed by Windows
+const maxFreezeOccurrences = 100
+
+// Freeze is a change freeze: a one-off date range (start, end) or a recurring window
+// (schedule, duration) during which deploys are gated whatever the SLO health
+type Freeze struct {
+	Name   string `yaml:"name"`
+	Reason string `yaml:"reason,omitempty"`
+	Action string `yaml:"action,omitempty"` // BLOCK (default) or WARN
+	// IANA timezone the times and schedule are in (default UTC)
+	Timezone string `yaml:"timezone,omitempty"`
+
+	// One-off freeze from start until end (exclusive): 2006-01-02, 2006-01-02T15:04 or RFC3339
+	Start string `yaml:"start,omitempty"`
+	End   string `yaml:"end,omitempty"`
+
+	// Recurring freeze starting at each match of a five-field cron schedule
+	Schedule string `yaml:"schedule,omitempty"`
+	Duration string `yaml:"duration,omitempty"`
+
+	// Services and environments the freeze applies to; empty applies to all
+	Services     []string `yaml:"services,omitempty"`
+	Environments []string `yaml:"environments,omitempty"`
+
+	location *time.Location
+	start    time.Time
+	end      time.Time
+	schedule *cronSchedule
+	duration time.Duration
+}
+
+// FreezeCalendar is the set of change freezes applied to every gate decision
+type FreezeCalendar struct {
+	Freezes []Freeze `yaml:"freezes"`
+}
+
+// FreezeWindow is one occurrence of a freeze
+type FreezeWindow struct {
+	Freeze *Freeze
+	Start  time.Time
+	End    time.Time
+}
+
+// Active reports whether the window covers t
+func (w FreezeWindow) Active(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// LoadFreezeCalendar reads and validates a freeze calendar YAML file
+func LoadFreezeCalendar(path string) (*FreezeCalendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read freeze calendar: %w", err)
+	}
+	return ParseFreezeCalendar(data)
+}
+
+// ParseFreezeCalendar parses and validates a freeze calendar
+func ParseFreezeCalendar(data []byte) (*FreezeCalendar, error) {
+	var calendar FreezeCalendar
+	if err := yaml.Unmarshal(data, &calendar); err != nil {
+		return nil, fmt.Errorf("failed to parse freeze calendar: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range calendar.Freezes {
+		freeze := &calendar.Freezes[i]
+		if freeze.Name == "" {
+			return nil, fmt.Errorf("freezes[%d]: name is required", i)
+		}
+		if seen[freeze.Name] {
+			return nil, fmt.Errorf("freeze %q: duplicate name", freeze.Name)
+		}
+		seen[freeze.Name] = true
+
+		if err := freeze.compile(); err != nil {
+			return nil, fmt.Errorf("freeze %q: %w", freeze.Name, err)
+		}
+	}
+
+	return &calendar, nil
+}
+
+// compile validates a freeze and resolves its timezone, times and schedule
+func (f *Freeze) compile() error {
+	switch f.Action {
+	case "":
+		f.Action = string(DecisionBLOCK)
+	case string(DecisionBLOCK), string(DecisionWARN):
+	default:
+		return fmt.Errorf("action must be BLOCK or WARN, got %q", f.Action)
+	}
+
+	f.location = time.UTC
+	if f.Timezone != "" {
+		loc, err := time.LoadLocation(f.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+		f.location = loc
+	}
+
+	oneOff := f.Start != "" || f.End != ""
+	recurring := f.Schedule != "" || f.Duration != ""
+	switch {
+	case oneOff && recurring:
+		return fmt.Errorf("set either start and end or schedule and duration, not both")
+
+	case oneOff:
+		var err error
+		if f.start, err = parseFreezeTime(f.Start, f.location); err != nil {
+			return fmt.Errorf("start: %w", err)
+		}
+		if f.end, err = parseFreezeTime(f.End, f.location); err != nil {
+			return fmt.Errorf("end: %w", err)
+		}
+		if !f.end.After(f.start) {
+			return fmt.Errorf("end must be after start")
+		}
+
+	case recurring:
+		if f.Schedule == "" || f.Duration == "" {
+			return fmt.Errorf("a recurring freeze needs both schedule and duration")
+		}
+		schedule, err := parseCron(f.Schedule)
+		if err != nil {
+			return fmt.Errorf("invalid schedule: %w", err)
+		}
+		duration, err := slo.ParseDuration(f.Duration)
+		if err != nil {
+			return fmt.Errorf("invalid duration: %w", err)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("duration must be positive")
+		}
+		f.schedule, f.duration = schedule, duration
+
+	default:
+		return fmt.Errorf("set start and end, or schedule and duration")
+	}
+
+	return nil
+}
+
+// parseFreezeTime parses a date, local date and time, or RFC3339 time
+func parseFreezeTime(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("is required")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
+
+// Applies reports whether the freeze covers a service and environment
+func (f *Freeze) Applies(service, environment string) bool {
+	if len(f.Services) > 0 && !slices.Contains(f.Services, service) {
+		return false
+	}
+	if len(f.Environments) > 0 && !slices.Contains(f.Environments, environment) {
+		return false
+	}
+	return true
+}
+
+// windows returns the occurrences of the freeze that overlap [from, to]
+func (f *Freeze) windows(from, to time.Time) []FreezeWindow {
+	if f.schedule == nil {
+		if f.end.After(from) && !f.start.After(to) {
+			return []FreezeWindow{{Freeze: f, Start: f.start, End: f.end}}
+		}
+		return nil
+	}
+
+	var windows []FreezeWindow
+	start := f.schedule.next(from.Add(-f.duration), f.location)
+	for !start.IsZero() && !start.After(to) && len(windows) < maxFreezeOccurrences {
+		windows = append(windows, FreezeWindow{Freeze: f, Start: start, End: start.Add(f.duration)})
+		start = f.schedule.next(start, f.location)
+	}
+	return windows
+}
+
+// Active returns the freeze windows covering t that apply to a service and environment,
+// ordered by start
+func (c *FreezeCalendar) Active(service, environment string, t time.Time) []FreezeWindow {
+	var active []FreezeWindow
+	for i := range c.Freezes {
+		freeze := &c.Freezes[i]
+		if !freeze.Applies(service, environment) {
+			continue
+		}
+		for _, window := range freeze.windows(t, t) {
+			if window.Active(t) {
+				active = append(active, window)
+			}
+		}
+	}
+	sortFreezeWindows(active)
+	return active
+}
+
+// Windows returns the freeze windows active at t or starting within horizon after it,
+// ordered by start
+func (c *FreezeCalendar) Windows(t time.Time, horizon time.Duration) []FreezeWindow {
+	var windows []FreezeWindow
+	for i := range c.Freezes {
+		windows = append(windows, c.Freezes[i].windows(t, t.Add(horizon))...)
+	}
+	sortFreezeWindows(windows)
+	return windows
+}
+
+// sortFreezeWindows orders windows by start, then freeze name
+func sortFreezeWindows(windows []FreezeWindow) {
+	sort.SliceStable(windows, func(i, j int) bool {
+		if !windows[i].Start.Equal(windows[j].Start) {
+			return windows[i].Start.Before(windows[j].Start)
+		}
+		return windows[i].Freeze.Name < windows[j].Freeze.Name
+	})
+}
@@ -0,0 +1,235 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/eval"
+)
+
+func TestCronSchedule_Next(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		expr     string
+		after    time.Time
+		loc      *time.Location
+		expected time.Time
+	}{
+		{
+			name:     "every 15 minutes",
+			expr:     "*/15 * * * *",
+			after:    time.Date(2026, 3, 2, 10, 7, 30, 0, time.UTC),
+			loc:      time.UTC,
+			expected: time.Date(2026, 3, 2, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:     "strictly after a match",
+			expr:     "0 17 * * FRI",
+			after:    time.Date(2026, 3, 6, 17, 0, 0, 0, time.UTC),
+			loc:      time.UTC,
+			expected: time.Date(2026, 3, 13, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekday range in a timezone",
+			expr:     "30 9 * * mon-fri",
+			after:    time.Date(2026, 3, 7, 12, 0, 0, 0, berlin), // Saturday
+			loc:      berlin,
+			expected: time.Date(2026, 3, 9, 9, 30, 0, 0, berlin),
+		},
+		{
+			name:     "day of month or day of week",
+			expr:     "0 0 1 * 0",
+			after:    time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), // Monday
+			loc:      time.UTC,
+			expected: time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), // Sunday before April 1st
+		},
+		{
+			name:     "month names and year wrap",
+			expr:     "0 0 20 DEC *",
+			after:    time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC),
+			loc:      time.UTC,
+			expected: time.Date(2027, 12, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "never matches",
+			expr:  "0 0 30 FEB *",
+			after: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			loc:   time.UTC,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if next := schedule.next(tt.after, tt.loc); !next.Equal(tt.expected) {
+				t.Errorf("expected %s, got %s", tt.expected, next)
+			}
+		})
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "0 0 * * FUNDAY", "5-1 * * * *"} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("expected an error for %q", expr)
+		}
+	}
+}
+
+const testFreezeCalendar = `
+freezes:
+  - name: holidays
+    reason: Year-end code freeze
+    start: 2026-12-20
+    end: 2027-01-04
+  - name: friday-evening
+    action: WARN
+    timezone: Europe/Berlin
+    schedule: "0 17 * * FRI"
+    duration: 64h
+    environments: [prod]
+  - name: payments-migration
+    start: 2026-03-10T08:00:00Z
+    end: 2026-03-10T12:00:00Z
+    services: [payment]
+`
+
+func TestFreezeCalendar_Active(t *testing.T) {
+	calendar, err := ParseFreezeCalendar([]byte(testFreezeCalendar))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		service     string
+		environment string
+		at          time.Time
+		expected    []string
+	}{
+		{name: "no freeze", service: "checkout", environment: "prod", at: time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)},
+		{name: "holiday", service: "checkout", environment: "staging", at: time.Date(2026, 12, 24, 12, 0, 0, 0, time.UTC), expected: []string{"holidays"}},
+		{name: "holiday end is exclusive", service: "checkout", environment: "staging", at: time.Date(2027, 1, 4, 0, 0, 0, 0, time.UTC)},
+		// Friday 2026-03-06 17:00 Berlin is 16:00 UTC; the freeze runs until Monday 09:00 Berlin
+		{name: "friday evening", service: "checkout", environment: "prod", at: time.Date(2026, 3, 6, 16, 30, 0, 0, time.UTC), expected: []string{"friday-evening"}},
+		{name: "before friday evening", service: "checkout", environment: "prod", at: time.Date(2026, 3, 6, 15, 59, 0, 0, time.UTC)},
+		{name: "weekend", service: "checkout", environment: "prod", at: time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC), expected: []string{"friday-evening"}},
+		{name: "monday morning", service: "checkout", environment: "prod", at: time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)},
+		{name: "other environment", service: "checkout", environment: "staging", at: time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC)},
+		{name: "scoped to service", service: "payment", environment: "staging", at: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC), expected: []string{"payments-migration"}},
+		{name: "other service", service: "checkout", environment: "staging", at: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)},
+		{name: "overlapping", service: "payment", environment: "prod", at: time.Date(2026, 12, 25, 20, 0, 0, 0, time.UTC), expected: []string{"holidays", "friday-evening"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, window := range calendar.Active(tt.service, tt.environment, tt.at) {
+				names = append(names, window.Freeze.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected freezes %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestFreezeCalendar_Windows(t *testing.T) {
+	calendar, err := ParseFreezeCalendar([]byte(testFreezeCalendar))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Sunday 2026-03-08: the weekend freeze is active, then the migration and the next
+	// Friday evening follow within a week
+	now := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	windows := calendar.Windows(now, 7*24*time.Hour)
+
+	expected := []struct {
+		name   string
+		start  time.Time
+		active bool
+	}{
+		{name: "friday-evening", start: time.Date(2026, 3, 6, 16, 0, 0, 0, time.UTC), active: true},
+		{name: "payments-migration", start: time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)},
+		{name: "friday-evening", start: time.Date(2026, 3, 13, 16, 0, 0, 0, time.UTC)},
+	}
+	if len(windows) != len(expected) {
+		t.Fatalf("expected %d windows, got %+v", len(expected), windows)
+	}
+	for i, window := range windows {
+		if window.Freeze.Name != expected[i].name || !window.Start.Equal(expected[i].start) || window.Active(now) != expected[i].active {
+			t.Errorf("window %d: expected %s at %s (active %v), got %s at %s", i,
+				expected[i].name, expected[i].start, expected[i].active, window.Freeze.Name, window.Start)
+		}
+	}
+}
+
+func TestParseFreezeCalendar_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		calendar string
+		expected string
+	}{
+		{name: "missing name", calendar: "freezes: [{start: 2026-01-01, end: 2026-01-02}]", expected: "name is required"},
+		{name: "duplicate name", calendar: "freezes: [{name: a, start: 2026-01-01, end: 2026-01-02}, {name: a, start: 2026-01-01, end: 2026-01-02}]", expected: "duplicate name"},
+		{name: "no window", calendar: "freezes: [{name: a}]", expected: "set start and end, or schedule and duration"},
+		{name: "both kinds", calendar: "freezes: [{name: a, start: 2026-01-01, end: 2026-01-02, schedule: '0 17 * * 5'}]", expected: "not both"},
+		{name: "end before start", calendar: "freezes: [{name: a, start: 2026-01-02, end: 2026-01-01}]", expected: "end must be after start"},
+		{name: "schedule without duration", calendar: "freezes: [{name: a, schedule: '0 17 * * 5'}]", expected: "needs both schedule and duration"},
+		{name: "invalid schedule", calendar: "freezes: [{name: a, schedule: '0 25 * * *', duration: 1h}]", expected: "invalid schedule"},
+		{name: "invalid timezone", calendar: "freezes: [{name: a, timezone: Mars/Olympus, start: 2026-01-01, end: 2026-01-02}]", expected: "invalid timezone"},
+		{name: "allow action", calendar: "freezes: [{name: a, action: ALLOW, start: 2026-01-01, end: 2026-01-02}]", expected: "action must be BLOCK or WARN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFreezeCalendar([]byte(tt.calendar))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestEngine_FreezeCalendar(t *testing.T) {
+	calendar, err := ParseFreezeCalendar([]byte(testFreezeCalendar))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	engine := NewEngine()
+	engine.SetFreezeCalendar(calendar)
+
+	sloSpec := createTestSLO()
+	sloSpec.Metadata.Service = "checkout"
+	sloSpec.Spec.Environment = "prod"
+
+	result := engine.Evaluate(sloSpec, &eval.EvaluationResult{
+		SLOID:     "test",
+		Timestamp: time.Date(2026, 12, 24, 12, 0, 0, 0, time.UTC),
+		BurnRates: map[string]eval.BurnRateResult{
+			"5m": {BurnRate: 1.0},
+			"1h": {BurnRate: 1.0},
+		},
+	})
+
+	if result.Decision != DecisionBLOCK {
+		t.Errorf("expected BLOCK during the holiday freeze, got %s (reasons: %v)", result.Decision, result.Reasons)
+	}
+	if len(result.Freezes) != 1 || result.Freezes[0] != "holidays" {
+		t.Errorf("expected the holidays freeze, got %v", result.Freezes)
+	}
+	expected := "change freeze holidays until 2027-01-04T00:00:00Z: Year-end code freeze"
+	if len(result.Reasons) != 1 || result.Reasons[0] != expected {
+		t.Errorf("expected reason %q, got %v", expected, result.Reasons)
+	}
+}
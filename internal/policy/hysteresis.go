@@ -0,0 +1,162 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/eval"
+	"github.com/samijaber1/aegis-slo/internal/slo"
+)
+
+// RuleState is the hysteresis state of a burn rule of an SLO. A rule with no state is
+// idle: neither pending nor fired.
+type RuleState struct {
+	SLOID         string
+	Target        string // latency_distribution target, empty otherwise
+	Rule          string
+	PendingSince  time.Time // triggered since, waiting for the rule's for duration
+	FiringSince   time.Time // fired since
+	ClearingSince time.Time // fired, below the clear level since, waiting for the cooldown
+}
+
+// idle reports whether the rule is neither pending nor fired
+func (s RuleState) idle() bool {
+	return s.PendingSince.IsZero() && s.FiringSince.IsZero()
+}
+
+// ruleKey identifies a rule within an SLO
+type ruleKey struct {
+	target string
+	rule   string
+}
+
+// RuleStates returns the hysteresis state of the pending and fired rules of an SLO,
+// ordered by target and rule
+func (e *Engine) RuleStates(sloID string) []RuleState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	states := make([]RuleState, 0, len(e.ruleStates[sloID]))
+	for _, state := range e.ruleStates[sloID] {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Target != states[j].Target {
+			return states[i].Target < states[j].Target
+		}
+		return states[i].Rule < states[j].Rule
+	})
+	return states
+}
+
+// RestoreRuleStates replaces the hysteresis state of the SLOs the given states belong to,
+// e.g. with the state persisted before a restart
+func (e *Engine) RestoreRuleStates(states []RuleState) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, state := range states {
+		delete(e.ruleStates, state.SLOID)
+	}
+	for _, state := range states {
+		if state.idle() {
+			continue
+		}
+		if e.ruleStates[state.SLOID] == nil {
+			e.ruleStates[state.SLOID] = make(map[ruleKey]RuleState)
+		}
+		e.ruleStates[state.SLOID][ruleKey{target: state.Target, rule: state.Rule}] = state
+	}
+}
+
+// DeleteRuleStates drops the hysteresis state of an SLO, e.g. one that was removed
+func (e *Engine) DeleteRuleStates(sloID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.ruleStates, sloID)
+}
+
+// applyHysteresis applies a burn rule's for, clearBelow and cooldown to its result and
+// advances its state to the evaluation time. A rule whose burn rates are unknown keeps
+// its state.
+func applyHysteresis(rule slo.BurnRule, evalResult *eval.EvaluationResult, ruleResult *RuleResult, state *RuleState) {
+	shortBurn, shortExists := evalResult.BurnRates[rule.ShortWindow]
+	longBurn, longExists := evalResult.BurnRates[rule.LongWindow]
+	if !shortExists || !longExists || shortBurn.InsufficientEvents || longBurn.InsufficientEvents {
+		return
+	}
+
+	now := evalResult.Timestamp
+	forDuration, _ := slo.ParseDuration(rule.For)   // validated on load
+	cooldown, _ := slo.ParseDuration(rule.Cooldown) // validated on load
+
+	if state.FiringSince.IsZero() {
+		if !ruleResult.Triggered {
+			state.PendingSince = time.Time{}
+			return
+		}
+		if state.PendingSince.IsZero() {
+			state.PendingSince = now
+		}
+		if pending := now.Sub(state.PendingSince); pending < forDuration {
+			ruleResult.Triggered = false
+			ruleResult.Pending = true
+			ruleResult.Reason = fmt.Sprintf(
+				"rule %s pending for %s of %s: short=%s, long=%s (threshold=%.2fx)",
+				rule.Name,
+				pending.Truncate(time.Second),
+				rule.For,
+				formatBurnRate(shortBurn),
+				formatBurnRate(longBurn),
+				rule.Threshold,
+			)
+			return
+		}
+		state.PendingSince = time.Time{}
+		state.FiringSince = now
+		return
+	}
+
+	// A fired rule holds while both burn rates are at or above the clear level
+	clearLevel := rule.ClearLevel()
+	if shortBurn.GatedBurnRate() >= clearLevel && longBurn.GatedBurnRate() >= clearLevel {
+		state.ClearingSince = time.Time{}
+		if !ruleResult.Triggered {
+			ruleResult.Triggered = true
+			ruleResult.Holding = true
+			ruleResult.Reason = fmt.Sprintf(
+				"rule %s holding since %s: short=%s, long=%s (clears below %.2fx)",
+				rule.Name,
+				state.FiringSince.UTC().Format(time.RFC3339),
+				formatBurnRate(shortBurn),
+				formatBurnRate(longBurn),
+				clearLevel,
+			)
+		}
+		return
+	}
+
+	if state.ClearingSince.IsZero() {
+		state.ClearingSince = now
+	}
+	if clearing := now.Sub(state.ClearingSince); clearing < cooldown {
+		ruleResult.Triggered = true
+		ruleResult.Holding = true
+		ruleResult.Reason = fmt.Sprintf(
+			"rule %s holding since %s: below %.2fx for %s of %s cooldown",
+			rule.Name,
+			state.FiringSince.UTC().Format(time.RFC3339),
+			clearLevel,
+			clearing.Truncate(time.Second),
+			rule.Cooldown,
+		)
+		return
+	}
+
+	// Cleared
+	state.FiringSince = time.Time{}
+	state.ClearingSince = time.Time{}
+	ruleResult.Triggered = false
+}
@@ -0,0 +1,105 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/eval"
+)
+
+func TestEngine_Hysteresis(t *testing.T) {
+	engine := NewEngine()
+
+	sloSpec := createTestSLO()
+	rule := &sloSpec.Spec.BurnPolicy.Rules[0]
+	rule.For = "2m"
+	rule.ClearBelow = 7.0
+	rule.Cooldown = "5m"
+
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	// One evaluation per minute; burn is the rate of both windows
+	steps := []struct {
+		burn     float64
+		expected Decision
+		reason   string
+	}{
+		{burn: 15, expected: DecisionALLOW, reason: "rule fast-burn pending for 0s of 2m"},
+		{burn: 15, expected: DecisionALLOW, reason: "rule fast-burn pending for 1m0s of 2m"},
+		{burn: 15, expected: DecisionBLOCK, reason: "rule fast-burn triggered"},
+		{burn: 10, expected: DecisionBLOCK, reason: "rule fast-burn holding since 2026-03-02T12:02:00Z"},
+		{burn: 5, expected: DecisionBLOCK, reason: "below 7.00x for 0s of 5m cooldown"},
+		{burn: 8, expected: DecisionBLOCK, reason: "(clears below 7.00x)"}, // cooldown restarts
+		{burn: 5, expected: DecisionBLOCK, reason: "below 7.00x for 0s of 5m cooldown"},
+		{burn: 5, expected: DecisionBLOCK, reason: "below 7.00x for 1m0s of 5m cooldown"},
+		{burn: 5, expected: DecisionBLOCK, reason: "below 7.00x for 2m0s of 5m cooldown"},
+		{burn: 5, expected: DecisionBLOCK, reason: "below 7.00x for 3m0s of 5m cooldown"},
+		{burn: 5, expected: DecisionBLOCK, reason: "below 7.00x for 4m0s of 5m cooldown"},
+		{burn: 5, expected: DecisionALLOW, reason: "all burn rate checks passed"},
+		{burn: 15, expected: DecisionALLOW, reason: "rule fast-burn pending for 0s of 2m"},
+		{burn: 1, expected: DecisionALLOW, reason: "all burn rate checks passed"}, // pending resets
+	}
+
+	for i, step := range steps {
+		result := engine.Evaluate(sloSpec, &eval.EvaluationResult{
+			SLOID:     "test-slo",
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			BurnRates: map[string]eval.BurnRateResult{
+				"5m": {BurnRate: step.burn},
+				"1h": {BurnRate: step.burn},
+			},
+		})
+
+		if result.Decision != step.expected {
+			t.Errorf("step %d: expected %s, got %s (reasons: %v)", i, step.expected, result.Decision, result.Reasons)
+		}
+		if len(result.Reasons) != 1 || !strings.Contains(result.Reasons[0], step.reason) {
+			t.Errorf("step %d: expected a reason containing %q, got %v", i, step.reason, result.Reasons)
+		}
+	}
+
+	if states := engine.RuleStates("test-slo"); len(states) != 0 {
+		t.Errorf("expected no state once the rule is idle, got %+v", states)
+	}
+}
+
+func TestEngine_RestoreRuleStates(t *testing.T) {
+	sloSpec := createTestSLO()
+	sloSpec.Spec.BurnPolicy.Rules[0].Cooldown = "10m"
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	evalResult := &eval.EvaluationResult{
+		SLOID:     "test-slo",
+		Timestamp: now,
+		BurnRates: map[string]eval.BurnRateResult{
+			"5m": {BurnRate: 1.0},
+			"1h": {BurnRate: 1.0},
+		},
+	}
+
+	// A restarted engine without the state lets the rule clear at once
+	if result := NewEngine().Evaluate(sloSpec, evalResult); result.Decision != DecisionALLOW {
+		t.Errorf("expected ALLOW without state, got %s", result.Decision)
+	}
+
+	engine := NewEngine()
+	engine.RestoreRuleStates([]RuleState{
+		{SLOID: "test-slo", Rule: "fast-burn", FiringSince: now.Add(-time.Hour), ClearingSince: now.Add(-5 * time.Minute)},
+	})
+
+	result := engine.Evaluate(sloSpec, evalResult)
+	if result.Decision != DecisionBLOCK || !result.RuleResults[0].Holding {
+		t.Errorf("expected the restored rule to hold, got %s (reasons: %v)", result.Decision, result.Reasons)
+	}
+
+	states := engine.RuleStates("test-slo")
+	if len(states) != 1 || !states[0].ClearingSince.Equal(now.Add(-5*time.Minute)) {
+		t.Errorf("expected the clearing state to be kept, got %+v", states)
+	}
+
+	// Clones start without state
+	if states := engine.Clone().RuleStates("test-slo"); len(states) != 0 {
+		t.Errorf("expected a clone without state, got %+v", states)
+	}
+}
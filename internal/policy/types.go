@@ -9,15 +9,26 @@ const (
 	DecisionBLOCK Decision = "BLOCK"
 )
 
-// RuleResult represents the result of evaluating a burn rule
+// Rule kinds
+const (
+	RuleKindBurnRate = "burn_rate" // multi-window burn rate rule
+	RuleKindBudget   = "budget"    // budget remaining rule
+)
+
+// RuleResult represents the result of evaluating a burn or budget rule
 type RuleResult struct {
-	RuleName      string
-	Triggered     bool
-	Action        Decision
-	ShortBurnRate float64
-	LongBurnRate  float64
-	Threshold     float64
-	Reason        string
+	RuleName        string
+	Kind            string
+	Target          string // latency_distribution target, empty otherwise
+	Triggered       bool
+	Pending         bool // triggered, but not for the rule's for duration yet
+	Holding         bool // fired and not cleared yet, though no longer above the threshold
+	Action          Decision
+	ShortBurnRate   float64
+	LongBurnRate    float64
+	BudgetRemaining float64 // budget rules only
+	Threshold       float64 // burn rate, or budget fraction for budget rules
+	Reason          string
 }
 
 // GateResult represents the final gate decision
@@ -27,4 +38,6 @@ type GateResult struct {
 	Reasons      []string
 	IsStale      bool
 	HasNoTraffic bool
+	QueryFailed  bool     // the evaluation or some of its queries failed
+	Freezes      []string // names of the change freezes in effect
 }
@@ -0,0 +1,301 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/adapter/synthetic"
+	"github.com/samijaber1/aegis-slo/internal/eval"
+	"github.com/samijaber1/aegis-slo/internal/policy"
+	"github.com/samijaber1/aegis-slo/internal/slo"
+	"github.com/samijaber1/aegis-slo/internal/storage"
+	"github.com/samijaber1/aegis-slo/internal/storage/sqlite"
+)
+
+// timeRecorder answers every ratio query with a healthy ratio and records the query times
+type timeRecorder struct {
+	mu    sync.Mutex
+	times map[time.Time]bool
+}
+
+func (r *timeRecorder) QueryRatio(ctx context.Context, query eval.RatioQuery) (eval.RatioResult, error) {
+	r.mu.Lock()
+	r.times[query.Time] = true
+	r.mu.Unlock()
+
+	ts := query.Time
+	return eval.RatioResult{
+		Window: query.Window,
+		Good:   eval.SeriesValue{Value: 999, Timestamp: &ts},
+		Total:  eval.SeriesValue{Value: 1000, Timestamp: &ts},
+	}, nil
+}
+
+// auditTestSLO returns a ratio SLO with a 5m/1h burn rule for scheduler tests that store evaluations
+func auditTestSLO() *slo.SLO {
+	return &slo.SLO{
+		Kind:     slo.KindSLO,
+		Metadata: slo.Metadata{ID: "api-availability", Service: "api"},
+		Spec: slo.Spec{
+			Environment:        "prod",
+			Objective:          0.99,
+			ComplianceWindow:   "30d",
+			EvaluationInterval: "1m",
+			SLI: slo.SLI{
+				Type:  "ratio",
+				Good:  slo.QueryRef{PrometheusQuery: "good"},
+				Total: slo.QueryRef{PrometheusQuery: "total"},
+			},
+			BurnPolicy: slo.BurnPolicy{Rules: []slo.BurnRule{
+				{Name: "fast", ShortWindow: "5m", LongWindow: "1h", Threshold: 14, Action: "BLOCK"},
+			}},
+			Gating: slo.Gating{StalenessLimit: "120s"},
+		},
+	}
+}
+
+func TestScheduler_Backfill(t *testing.T) {
+	recorder := &timeRecorder{times: make(map[time.Time]bool)}
+	sched := NewScheduler(eval.NewEvaluator(recorder), policy.NewEngine(), "")
+
+	store, err := sqlite.NewStore(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	sched.SetAuditStorage(store)
+
+	sloSpec := auditTestSLO()
+	sched.SetSLOsForTest([]slo.SLOWithFile{{SLO: sloSpec}})
+	if err := store.StoreSLODefinition(sloSpec); err != nil {
+		t.Fatalf("failed to store SLO definition: %v", err)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	result, err := sched.Backfill(context.Background(), "api-availability", start, end, time.Hour)
+	if err != nil {
+		t.Fatalf("backfill failed: %v", err)
+	}
+	if result.Evaluations != 3 || result.Decisions[policy.DecisionALLOW] != 3 {
+		t.Errorf("expected 3 ALLOW evaluations, got %+v", result)
+	}
+
+	for at := start; !at.After(end); at = at.Add(time.Hour) {
+		if !recorder.times[at] {
+			t.Errorf("expected queries at %s", at)
+		}
+	}
+
+	backfilled := true
+	records, err := store.QueryAudit(storage.AuditFilter{SLOID: "api-availability", Backfilled: &backfilled})
+	if err != nil {
+		t.Fatalf("failed to query audit: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 backfilled records, got %d", len(records))
+	}
+	if !records[0].Timestamp.Equal(end) || records[0].IsStale {
+		t.Errorf("expected fresh record at %s, got %s (stale=%v)", end, records[0].Timestamp, records[0].IsStale)
+	}
+
+	if _, ok := sched.GetCache().Get("api-availability"); ok {
+		t.Error("expected backfill to leave the state cache alone")
+	}
+	if state, _ := store.GetLatestState("api-availability"); state != nil {
+		t.Error("expected backfill to leave the latest state alone")
+	}
+}
+
+func TestScheduler_BackfillRejectsInvalidRanges(t *testing.T) {
+	sched := NewScheduler(eval.NewEvaluator(&timeRecorder{times: make(map[time.Time]bool)}), policy.NewEngine(), "")
+	sched.SetSLOsForTest([]slo.SLOWithFile{{SLO: &slo.SLO{Metadata: slo.Metadata{ID: "api"}}}})
+
+	start := time.Now().Add(-time.Hour)
+
+	if _, err := sched.Backfill(context.Background(), "api", start, start, time.Minute); err == nil {
+		t.Error("expected error without audit storage")
+	}
+
+	store, err := sqlite.NewStore(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	sched.SetAuditStorage(store)
+
+	tests := []struct {
+		name  string
+		sloID string
+		start time.Time
+		end   time.Time
+		step  time.Duration
+	}{
+		{"unknown SLO", "missing", start, start, time.Minute},
+		{"zero step", "api", start, start, 0},
+		{"end before start", "api", start, start.Add(-time.Minute), time.Minute},
+		{"end in future", "api", start, time.Now().Add(time.Hour), time.Minute},
+		{"too many points", "api", start, start.Add(time.Hour), time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := sched.Backfill(context.Background(), tt.sloID, tt.start, tt.end, tt.step)
+			if err == nil || result != nil {
+				t.Errorf("expected rejection, got result=%v err=%v", result, err)
+			}
+		})
+	}
+}
+
+func TestScheduler_BackfillDiscoversChildren(t *testing.T) {
+	adapter := synthetic.NewAdapter()
+	windows := map[string]synthetic.WindowData{}
+	for _, window := range []string{"5m", "1h", "30d"} {
+		windows[window] = synthetic.WindowData{Good: 999, Total: 1000}
+	}
+	adapter.SetFixture("acme", &synthetic.MetricFixture{Windows: windows})
+	adapter.SetLabelValues("customer", []string{"acme"})
+
+	sched := NewScheduler(eval.NewEvaluator(adapter), policy.NewEngine(), "")
+	store, err := sqlite.NewStore(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	sched.SetAuditStorage(store)
+
+	parent := auditTestSLO()
+	parent.Metadata.ID = "api-customer"
+	parent.Spec.ExpandBy = "customer"
+	parent.Spec.SLI.Good.PrometheusQuery = "{{customer}}"
+	parent.Spec.SLI.Total.PrometheusQuery = "{{customer}}"
+	sched.SetSLOsForTest([]slo.SLOWithFile{{SLO: parent}})
+
+	start := time.Now().Add(-2 * time.Hour)
+	end := start.Add(time.Hour)
+
+	// The child is resolved by discovery although the scheduler is not running
+	result, err := sched.Backfill(context.Background(), "api-customer-acme", start, end, time.Hour)
+	if err != nil {
+		t.Fatalf("backfill failed: %v", err)
+	}
+	if result.Evaluations != 2 {
+		t.Errorf("expected 2 evaluations, got %+v", result)
+	}
+
+	if _, err := sched.Backfill(context.Background(), "api-customer-initech", start, end, time.Hour); err == nil {
+		t.Error("expected an unreported child to be rejected")
+	}
+	if _, err := sched.Backfill(context.Background(), "api-customer", start, end, time.Hour); err == nil {
+		t.Error("expected the expandBy parent to be rejected")
+	}
+}
+
+// failingAdapter fails every ratio query
+type failingAdapter struct{}
+
+func (failingAdapter) QueryRatio(ctx context.Context, query eval.RatioQuery) (eval.RatioResult, error) {
+	return eval.RatioResult{}, fmt.Errorf("prometheus unavailable")
+}
+
+func TestScheduler_QueryFailureIsCachedAndAudited(t *testing.T) {
+	sched := NewScheduler(eval.NewEvaluator(failingAdapter{}), policy.NewEngine(), "")
+
+	store, err := sqlite.NewStore(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	sched.SetAuditStorage(store)
+
+	sloSpec := auditTestSLO()
+	if err := store.StoreSLODefinition(sloSpec); err != nil {
+		t.Fatalf("failed to store SLO definition: %v", err)
+	}
+
+	sched.SetSLOsForTest([]slo.SLOWithFile{{SLO: sloSpec, File: "api-availability.yaml"}})
+	if err := sched.evaluateOnce(context.Background(), sloSpec, time.Minute); err != nil {
+		t.Fatalf("evaluateOnce failed: %v", err)
+	}
+
+	state, ok := sched.GetCache().Get("api-availability")
+	if !ok {
+		t.Fatal("expected the failed evaluation to be cached")
+	}
+	if state.GateResult.Decision != policy.DecisionWARN || !state.GateResult.QueryFailed {
+		t.Errorf("expected WARN with QueryFailed, got %s (queryFailed=%v)", state.GateResult.Decision, state.GateResult.QueryFailed)
+	}
+	if len(state.EvalResult.QueryErrors) != 3 {
+		t.Errorf("expected query errors for 3 windows, got %v", state.EvalResult.QueryErrors)
+	}
+
+	records, err := store.QueryAudit(storage.AuditFilter{SLOID: "api-availability"})
+	if err != nil {
+		t.Fatalf("failed to query audit: %v", err)
+	}
+	if len(records) != 1 || !records[0].QueryFailed || records[0].Decision != string(policy.DecisionWARN) {
+		t.Errorf("expected one WARN audit record with QueryFailed, got %+v", records)
+	}
+
+	latest, err := store.GetLatestState("api-availability")
+	if err != nil || latest == nil || !latest.QueryFailed {
+		t.Errorf("expected latest state with QueryFailed, got %+v (err=%v)", latest, err)
+	}
+}
+
+func TestScheduler_ForecastUsesHistory(t *testing.T) {
+	sched := NewScheduler(eval.NewEvaluator(&timeRecorder{times: make(map[time.Time]bool)}), policy.NewEngine(), "")
+
+	store, err := sqlite.NewStore(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	sched.SetAuditStorage(store)
+
+	sloSpec := auditTestSLO()
+	if err := store.StoreSLODefinition(sloSpec); err != nil {
+		t.Fatalf("failed to store SLO definition: %v", err)
+	}
+
+	// Budget remaining has been falling over the last hour; the next evaluation reports 0.9
+	gateResult := &policy.GateResult{Decision: policy.DecisionALLOW, Reasons: []string{}}
+	for i, budget := range []float64{0.96, 0.93} {
+		evalResult := &eval.EvaluationResult{
+			SLOID:           "api-availability",
+			Timestamp:       time.Now().Add(-time.Duration(40-20*i) * time.Minute),
+			BudgetRemaining: budget,
+		}
+		if err := store.StoreEvaluation(evalResult, gateResult); err != nil {
+			t.Fatalf("failed to store evaluation: %v", err)
+		}
+	}
+
+	sched.SetSLOsForTest([]slo.SLOWithFile{{SLO: sloSpec, File: "api-availability.yaml"}})
+	if err := sched.evaluateOnce(context.Background(), sloSpec, time.Minute); err != nil {
+		t.Fatalf("evaluateOnce failed: %v", err)
+	}
+
+	state, ok := sched.GetCache().Get("api-availability")
+	if !ok {
+		t.Fatal("expected the evaluation to be cached")
+	}
+	forecast := state.EvalResult.Forecast
+	if forecast == nil || forecast.Basis != eval.ForecastBasisHistory || forecast.TimeToExhaustion == nil {
+		t.Fatalf("expected a history forecast with a time to exhaustion, got %+v", forecast)
+	}
+
+	records, err := store.QueryAudit(storage.AuditFilter{SLOID: "api-availability", Limit: 1})
+	if err != nil {
+		t.Fatalf("failed to query audit: %v", err)
+	}
+	if len(records) != 1 || records[0].Forecast == nil || records[0].Forecast.Basis != eval.ForecastBasisHistory {
+		t.Errorf("expected the audit record to carry the history forecast, got %+v", records)
+	}
+}
@@ -11,7 +11,7 @@ import (
 // EvaluationState represents the cached evaluation state for an SLO
 type EvaluationState struct {
 	EvalResult *eval.EvaluationResult
-	GateResult *policy.GateResult
+	GateResult *policy.GateResult // without change freezes; see Scheduler.ApplyFreezes
 	UpdatedAt  time.Time
 	TTL        time.Duration
 }
@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/storage"
+)
+
+// DefaultOverrideExpiryInterval is how often the expiry of decision overrides is recorded
+const DefaultOverrideExpiryInterval = time.Minute
+
+// OverrideStorage returns the audit storage if it supports decision overrides
+func (s *Scheduler) OverrideStorage() (storage.OverrideStorage, bool) {
+	overrides, ok := s.GetAuditStorage().(storage.OverrideStorage)
+	return overrides, ok
+}
+
+// ExpireOverrides records the expiry of the decision overrides that expired by now
+func (s *Scheduler) ExpireOverrides(now time.Time) error {
+	overrides, ok := s.OverrideStorage()
+	if !ok {
+		return nil
+	}
+
+	expired, err := overrides.ExpireOverrides(now)
+	if err != nil {
+		return err
+	}
+	for _, override := range expired {
+		log.Printf("Override %d of SLO %s (%s, approved by %s) expired", override.ID, override.SLOID, override.Decision, override.Approver)
+	}
+	return nil
+}
+
+// WatchOverrides records the expiry of decision overrides every interval until ctx is
+// cancelled. Expired overrides stop applying immediately; this only writes the record.
+func (s *Scheduler) WatchOverrides(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.ExpireOverrides(now); err != nil {
+				log.Printf("Failed to record override expiry: %v", err)
+			}
+		}
+	}
+}
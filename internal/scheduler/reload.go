@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"log"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/slo"
+)
+
+// ReloadResult lists the SLO IDs affected by a reload
+type ReloadResult struct {
+	Added     []string
+	Removed   []string
+	Changed   []string
+	Unchanged int
+}
+
+// Reload reloads and revalidates SLOs from the configured sources. A failed
+// reload keeps the current set. A successful one diffs the sets by ID: removed
+// SLOs are stopped and their cached state dropped, changed SLOs are restarted,
+// added SLOs are started, and unchanged SLOs keep running untouched. Added and
+// changed definitions are persisted to audit storage.
+func (s *Scheduler) Reload() (*ReloadResult, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	next, err := s.loadFromSources()
+	if err != nil {
+		s.mu.RLock()
+		current := len(s.slos)
+		s.mu.RUnlock()
+		log.Printf("Reload failed, keeping %d loaded SLOs: %v", current, err)
+		return nil, err
+	}
+
+	s.mu.Lock()
+	previous := make(map[string]*slo.SLO, len(s.slos))
+	for _, sloWithFile := range s.slos {
+		previous[sloWithFile.SLO.Metadata.ID] = sloWithFile.SLO
+	}
+
+	result := &ReloadResult{}
+	var stored []*slo.SLO
+	for _, sloWithFile := range next {
+		id := sloWithFile.SLO.Metadata.ID
+		old, exists := previous[id]
+		delete(previous, id)
+
+		switch {
+		case !exists:
+			result.Added = append(result.Added, id)
+		case !reflect.DeepEqual(old, sloWithFile.SLO):
+			result.Changed = append(result.Changed, id)
+			if s.running {
+				s.stopLocked(id, sloWithFile.SLO.IsDynamic() != old.IsDynamic())
+			}
+		default:
+			result.Unchanged++
+			continue
+		}
+
+		stored = append(stored, sloWithFile.SLO)
+		if s.running {
+			s.startLocked(sloWithFile)
+		}
+	}
+
+	for id := range previous {
+		result.Removed = append(result.Removed, id)
+		s.stopLocked(id, true)
+	}
+	sort.Strings(result.Removed)
+
+	s.slos = next
+	audit := s.audit
+	s.mu.Unlock()
+
+	if audit != nil {
+		for _, sloSpec := range stored {
+			if err := audit.StoreSLODefinition(sloSpec); err != nil {
+				log.Printf("Warning: failed to store SLO definition %s: %v", sloSpec.Metadata.ID, err)
+			}
+		}
+	}
+
+	log.Printf("Reloaded SLOs: %d added, %d removed, %d changed, %d unchanged",
+		len(result.Added), len(result.Removed), len(result.Changed), result.Unchanged)
+	return result, nil
+}
+
+// Watch polls the configured sources every interval and reloads when their
+// content changes, until ctx is cancelled. HTTP sources are polled with their
+// ETag, so unchanged remote definitions are not re-downloaded.
+func (s *Scheduler) Watch(ctx context.Context, interval time.Duration) {
+	last := s.sourcesFingerprint()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := s.sourcesFingerprint()
+			if current == last {
+				continue
+			}
+			// Remember the content even if the reload fails, so a broken
+			// definition is reported once rather than on every poll
+			last = current
+
+			log.Println("SLO sources changed, reloading")
+			_, _ = s.Reload()
+		}
+	}
+}
+
+// sourcesFingerprint hashes the definitions and load errors currently reported by the sources
+func (s *Scheduler) sourcesFingerprint() [sha256.Size]byte {
+	s.mu.RLock()
+	sources := s.sources
+	s.mu.RUnlock()
+
+	slos, errors := slo.LoadSources(sources)
+	data, _ := json.Marshal(struct {
+		SLOs   []slo.SLOWithFile
+		Errors []slo.ValidationError
+	}{slos, errors})
+
+	return sha256.Sum256(data)
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/samijaber1/aegis-slo/internal/adapter/synthetic"
+	"github.com/samijaber1/aegis-slo/internal/eval"
+	"github.com/samijaber1/aegis-slo/internal/policy"
+)
+
+const reloadTestSLO = `apiVersion: aegis.dev/v1
+kind: SLO
+metadata:
+  id: %s
+  service: test
+spec:
+  environment: prod
+  objective: %s
+  complianceWindow: 30d
+  evaluationInterval: 1h
+  sli:
+    type: ratio
+    good:
+      prometheusQuery: healthy
+    total:
+      prometheusQuery: healthy
+  burnPolicy:
+    rules:
+      - name: fast-burn
+        shortWindow: 5m
+        longWindow: 1h
+        threshold: 14
+        action: BLOCK
+  gating:
+    minDataPoints: 1
+    stalenessLimit: 120s
+`
+
+func writeSLO(t *testing.T, dir, id, objective string) {
+	t.Helper()
+	content := fmt.Sprintf(reloadTestSLO, id, objective)
+	if err := os.WriteFile(filepath.Join(dir, id+".yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScheduler_Reload(t *testing.T) {
+	adapter := synthetic.NewAdapter()
+	windows := map[string]synthetic.WindowData{}
+	for _, window := range []string{"5m", "1h", "30d"} {
+		windows[window] = synthetic.WindowData{Good: 999, Total: 1000}
+	}
+	adapter.SetFixture("healthy", &synthetic.MetricFixture{Windows: windows})
+
+	dir := t.TempDir()
+	writeSLO(t, dir, "alpha", "0.99")
+	writeSLO(t, dir, "beta", "0.99")
+	writeSLO(t, dir, "gamma", "0.99")
+
+	sched := NewScheduler(eval.NewEvaluator(adapter), policy.NewEngine(), dir)
+	sched.SetSchemaPath("../../schemas/slo_v1.json")
+
+	if err := sched.LoadSLOs(); err != nil {
+		t.Fatalf("initial load failed: %v", err)
+	}
+	if err := sched.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	defer sched.Stop()
+
+	for _, id := range []string{"alpha", "beta", "gamma"} {
+		waitForCache(t, sched, id)
+	}
+	gammaState, _ := sched.GetCache().Get("gamma")
+
+	// Change alpha, remove beta, add delta, leave gamma alone
+	writeSLO(t, dir, "alpha", "0.995")
+	os.Remove(filepath.Join(dir, "beta.yaml"))
+	writeSLO(t, dir, "delta", "0.99")
+
+	result, err := sched.Reload()
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+
+	want := &ReloadResult{Added: []string{"delta"}, Removed: []string{"beta"}, Changed: []string{"alpha"}, Unchanged: 1}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("expected %+v, got %+v", want, result)
+	}
+
+	if _, ok := sched.GetCache().Get("beta"); ok {
+		t.Error("expected removed SLO to be dropped from cache")
+	}
+	waitForCache(t, sched, "delta")
+
+	// The unchanged SLO's goroutine was not restarted
+	if state, _ := sched.GetCache().Get("gamma"); state != gammaState {
+		t.Error("expected unchanged SLO to keep its cached state")
+	}
+
+	// A broken definition is rejected and the last good set kept
+	if err := os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("metadata: [unclosed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = sched.Reload()
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || len(loadErr.Errors) == 0 {
+		t.Fatalf("expected LoadError, got %v", err)
+	}
+	if got := len(sched.GetSLOs()); got != 3 {
+		t.Errorf("expected last good set of 3 SLOs to be kept, got %d", got)
+	}
+}
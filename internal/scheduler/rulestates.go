@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"log"
+
+	"github.com/samijaber1/aegis-slo/internal/slo"
+	"github.com/samijaber1/aegis-slo/internal/storage"
+)
+
+// restoreRuleStates loads the persisted hysteresis state of burn rules into the policy
+// engine, so that pending and fired rules survive restarts
+func (s *Scheduler) restoreRuleStates(audit storage.AuditStorage) {
+	ruleStates, ok := audit.(storage.RuleStateStorage)
+	if !ok {
+		return
+	}
+
+	states, err := ruleStates.LoadRuleStates()
+	if err != nil {
+		log.Printf("Warning: failed to load rule states: %v", err)
+		return
+	}
+	s.policyEngine.RestoreRuleStates(states)
+	if len(states) > 0 {
+		log.Printf("Restored the state of %d burn rules", len(states))
+	}
+}
+
+// saveRuleStates persists the hysteresis state of an SLO's burn rules
+func (s *Scheduler) saveRuleStates(audit storage.AuditStorage, sloSpec *slo.SLO) {
+	ruleStates, ok := audit.(storage.RuleStateStorage)
+	if !ok || !hasHysteresis(sloSpec) {
+		return
+	}
+
+	if err := ruleStates.SaveRuleStates(sloSpec.Metadata.ID, s.policyEngine.RuleStates(sloSpec.Metadata.ID)); err != nil {
+		log.Printf("Warning: failed to store rule states for SLO %s: %v", sloSpec.Metadata.ID, err)
+	}
+}
+
+// dropRuleStatesLocked deletes the hysteresis state of a stopped SLO from the policy
+// engine and the audit storage. Callers hold s.mu, under which evaluations also save
+// rule states, so a late evaluation cannot write the state back.
+func (s *Scheduler) dropRuleStatesLocked(sloID string) {
+	s.policyEngine.DeleteRuleStates(sloID)
+
+	ruleStates, ok := s.audit.(storage.RuleStateStorage)
+	if !ok {
+		return
+	}
+	if err := ruleStates.SaveRuleStates(sloID, nil); err != nil {
+		log.Printf("Warning: failed to delete rule states for SLO %s: %v", sloID, err)
+	}
+}
+
+// hasHysteresis reports whether any burn rule of an SLO has hysteresis
+func hasHysteresis(sloSpec *slo.SLO) bool {
+	for _, rule := range sloSpec.Spec.BurnPolicy.Rules {
+		if rule.HasHysteresis() {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,30 +15,85 @@ import (
 	"github.com/samijaber1/aegis-slo/internal/storage"
 )
 
+// DefaultSchemaPath is the SLO schema location relative to the working directory
+const DefaultSchemaPath = "schemas/slo_v1.json"
+
+// DefaultDiscoveryInterval is how often label values are rediscovered for expandBy SLOs
+const DefaultDiscoveryInterval = 5 * time.Minute
+
+// maxDynamicChildren caps the children generated per expandBy SLO
+const maxDynamicChildren = 200
+
+// maxForecastHistory bounds the past evaluations read to forecast an SLO's budget
+const maxForecastHistory = 2000
+
 // Scheduler manages periodic SLO evaluations
 type Scheduler struct {
-	evaluator    *eval.Evaluator
-	policyEngine *policy.Engine
-	cache        *StateCache
-	sloDirectory string
-	slos         []slo.SLOWithFile
-	audit        storage.AuditStorage
-	cancel       context.CancelFunc
-	wg           sync.WaitGroup
-	mu           sync.RWMutex
-	running      bool
-}
-
-// NewScheduler creates a new scheduler
+	evaluator         *eval.Evaluator
+	policyEngine      *policy.Engine
+	cache             *StateCache
+	sources           []slo.Source
+	schemaPath        string
+	slos              []slo.SLOWithFile
+	children          map[string]map[string]*dynamicChild // parent ID -> child ID -> child
+	runners           map[string]context.CancelFunc       // SLO ID -> cancel for its goroutine
+	discoveryInterval time.Duration
+	audit             storage.AuditStorage
+	ctx               context.Context
+	cancel            context.CancelFunc
+	wg                sync.WaitGroup
+	mu                sync.RWMutex
+	reloadMu          sync.Mutex
+	running           bool
+}
+
+// dynamicChild is a running child of an expandBy SLO
+type dynamicChild struct {
+	slo.SLOWithFile
+	cancel context.CancelFunc
+}
+
+// NewScheduler creates a new scheduler loading SLOs from sloDirectory.
+// Pass an empty directory and call SetSources to load from other sources.
 func NewScheduler(evaluator *eval.Evaluator, policyEngine *policy.Engine, sloDirectory string) *Scheduler {
+	var sources []slo.Source
+	if sloDirectory != "" {
+		sources = append(sources, &slo.DirectorySource{Path: sloDirectory})
+	}
+
 	return &Scheduler{
-		evaluator:    evaluator,
-		policyEngine: policyEngine,
-		cache:        NewStateCache(),
-		sloDirectory: sloDirectory,
+		evaluator:         evaluator,
+		policyEngine:      policyEngine,
+		cache:             NewStateCache(),
+		sources:           sources,
+		schemaPath:        DefaultSchemaPath,
+		children:          make(map[string]map[string]*dynamicChild),
+		runners:           make(map[string]context.CancelFunc),
+		discoveryInterval: DefaultDiscoveryInterval,
 	}
 }
 
+// SetSources replaces the sources SLOs are loaded from
+func (s *Scheduler) SetSources(sources ...slo.Source) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sources = sources
+}
+
+// SetSchemaPath sets the JSON schema SLOs are validated against
+func (s *Scheduler) SetSchemaPath(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schemaPath = path
+}
+
+// SetDiscoveryInterval sets how often label values are rediscovered for expandBy SLOs
+func (s *Scheduler) SetDiscoveryInterval(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.discoveryInterval = interval
+}
+
 // SetAuditStorage sets the audit storage backend (optional)
 func (s *Scheduler) SetAuditStorage(audit storage.AuditStorage) {
 	s.mu.Lock()
@@ -44,26 +101,11 @@ func (s *Scheduler) SetAuditStorage(audit storage.AuditStorage) {
 	s.audit = audit
 }
 
-// LoadSLOs loads SLOs from the configured directory
+// LoadSLOs loads and validates SLOs from the configured sources
 func (s *Scheduler) LoadSLOs() error {
-	sloFiles, errors := slo.LoadFromDirectory(s.sloDirectory)
-	if len(errors) > 0 {
-		return fmt.Errorf("failed to load SLOs: %d errors", len(errors))
-	}
-
-	if len(sloFiles) == 0 {
-		return fmt.Errorf("no SLOs found in %s", s.sloDirectory)
-	}
-
-	// Validate all SLOs
-	validator, err := slo.NewValidator("schemas/slo_v1.json")
+	sloFiles, err := s.loadFromSources()
 	if err != nil {
-		return fmt.Errorf("failed to create validator: %w", err)
-	}
-
-	validationErrors := validator.ValidateDirectory(s.sloDirectory)
-	if len(validationErrors) > 0 {
-		return fmt.Errorf("SLO validation failed: %d errors", len(validationErrors))
+		return err
 	}
 
 	s.mu.Lock()
@@ -84,6 +126,66 @@ func (s *Scheduler) LoadSLOs() error {
 	return nil
 }
 
+// LoadError reports the load or validation errors that rejected a set of SLOs
+type LoadError struct {
+	Reason string
+	Errors []slo.ValidationError
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("%s: %d errors", e.Reason, len(e.Errors))
+}
+
+// loadFromSources loads, validates and expands SLOs from the configured sources
+func (s *Scheduler) loadFromSources() ([]slo.SLOWithFile, error) {
+	s.mu.RLock()
+	sources := s.sources
+	schemaPath := s.schemaPath
+	s.mu.RUnlock()
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no SLO sources configured")
+	}
+
+	raw, errors := slo.LoadSources(sources)
+	if len(errors) > 0 {
+		for _, err := range errors {
+			log.Printf("SLO load error: %v", err)
+		}
+		return nil, &LoadError{Reason: "failed to load SLOs", Errors: errors}
+	}
+
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("no SLOs found in %s", sourceNames(sources))
+	}
+
+	// Validate all SLOs together so ID collisions across sources are caught
+	validator, err := slo.NewValidator(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator: %w", err)
+	}
+
+	validationErrors := validator.ValidateSLOs(raw)
+	if len(validationErrors) > 0 {
+		for _, err := range validationErrors {
+			log.Printf("SLO validation error: %v", err)
+		}
+		return nil, &LoadError{Reason: "SLO validation failed", Errors: validationErrors}
+	}
+
+	sloFiles, _ := slo.ExpandMatrix(raw)
+	return sloFiles, nil
+}
+
+// sourceNames joins source names for messages
+func sourceNames(sources []slo.Source) string {
+	names := make([]string, len(sources))
+	for i, source := range sources {
+		names[i] = source.Name()
+	}
+	return strings.Join(names, ", ")
+}
+
 // Start begins the evaluation scheduler
 func (s *Scheduler) Start() error {
 	s.mu.Lock()
@@ -97,20 +199,57 @@ func (s *Scheduler) Start() error {
 		return fmt.Errorf("no SLOs loaded, call LoadSLOs() first")
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	s.cancel = cancel
+	// Resume pending and fired burn rules where they were before a restart
+	if s.audit != nil {
+		s.restoreRuleStates(s.audit)
+	}
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
 	s.running = true
-	slos := s.slos
+
+	for _, sloWithFile := range s.slos {
+		s.startLocked(sloWithFile)
+	}
+	count := len(s.slos)
 	s.mu.Unlock()
 
-	// Start one goroutine per SLO
-	for _, sloWithFile := range slos {
-		s.wg.Add(1)
+	log.Printf("Started scheduler for %d SLOs", count)
+	return nil
+}
+
+// startLocked starts the goroutine of an SLO: an evaluation loop, or a discovery
+// loop that runs one evaluation loop per child for expandBy SLOs. s.mu must be held.
+func (s *Scheduler) startLocked(sloWithFile slo.SLOWithFile) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.runners[sloWithFile.SLO.Metadata.ID] = cancel
+
+	s.wg.Add(1)
+	if sloWithFile.SLO.IsDynamic() {
+		go s.discoveryLoop(ctx, sloWithFile)
+	} else {
 		go s.evaluateLoop(ctx, sloWithFile.SLO)
 	}
+}
 
-	log.Printf("Started scheduler for %d SLOs", len(slos))
-	return nil
+// stopLocked stops the goroutine of an SLO along with any expandBy children.
+// Children's cached state and rule states are always dropped since the next discovery
+// may not recreate them; the SLO's own only when dropState is set. s.mu must be held.
+func (s *Scheduler) stopLocked(sloID string, dropState bool) {
+	if cancel, ok := s.runners[sloID]; ok {
+		cancel()
+		delete(s.runners, sloID)
+	}
+
+	for childID := range s.children[sloID] {
+		s.cache.Delete(childID)
+		s.dropRuleStatesLocked(childID)
+	}
+	delete(s.children, sloID)
+
+	if dropState {
+		s.cache.Delete(sloID)
+		s.dropRuleStatesLocked(sloID)
+	}
 }
 
 // Stop stops the scheduler and waits for all evaluations to complete
@@ -127,9 +266,117 @@ func (s *Scheduler) Stop() {
 
 	log.Println("Stopping scheduler...")
 	s.wg.Wait()
+
+	s.mu.Lock()
+	s.children = make(map[string]map[string]*dynamicChild)
+	s.runners = make(map[string]context.CancelFunc)
+	s.mu.Unlock()
+
 	log.Println("Scheduler stopped")
 }
 
+// discoveryLoop periodically discovers the label values of an expandBy SLO and
+// keeps one evaluation goroutine running per child
+func (s *Scheduler) discoveryLoop(ctx context.Context, parent slo.SLOWithFile) {
+	defer s.wg.Done()
+
+	s.discoverChildren(ctx, parent)
+
+	s.mu.RLock()
+	interval := s.discoveryInterval
+	s.mu.RUnlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.discoverChildren(ctx, parent)
+		}
+	}
+}
+
+// discoverChildren reconciles the running children of an expandBy SLO with the
+// label values currently reported by the adapter. New values start a child, and
+// children whose value disappeared are stopped and dropped from the cache.
+// On discovery errors the existing children keep running.
+func (s *Scheduler) discoverChildren(ctx context.Context, parent slo.SLOWithFile) {
+	parentID := parent.SLO.Metadata.ID
+
+	values, err := s.evaluator.DiscoverLabelValues(ctx, parent.SLO)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Error discovering children of SLO %s: %v", parentID, err)
+		return
+	}
+
+	if len(values) > maxDynamicChildren {
+		log.Printf("Warning: SLO %s has %d values for %s, keeping the first %d",
+			parentID, len(values), parent.SLO.Spec.ExpandBy, maxDynamicChildren)
+		sort.Strings(values)
+		values = values[:maxDynamicChildren]
+	}
+
+	children, err := slo.ExpandLabelValues(parent, values)
+	if err != nil {
+		log.Printf("Error expanding SLO %s: %v", parentID, err)
+		return
+	}
+
+	s.mu.Lock()
+	if ctx.Err() != nil {
+		s.mu.Unlock()
+		return
+	}
+
+	retired := s.children[parentID]
+	next := make(map[string]*dynamicChild, len(children))
+	var started []slo.SLOWithFile
+	for _, child := range children {
+		id := child.SLO.Metadata.ID
+		if existing, ok := retired[id]; ok {
+			next[id] = existing
+			delete(retired, id)
+			continue
+		}
+
+		childCtx, cancel := context.WithCancel(ctx)
+		next[id] = &dynamicChild{SLOWithFile: child, cancel: cancel}
+		started = append(started, child)
+
+		s.wg.Add(1)
+		go s.evaluateLoop(childCtx, child.SLO)
+	}
+	s.children[parentID] = next
+	audit := s.audit
+
+	// Retired children are cancelled and dropped under s.mu, like stopped SLOs
+	for id, child := range retired {
+		child.cancel()
+		s.cache.Delete(id)
+		s.dropRuleStatesLocked(id)
+	}
+	s.mu.Unlock()
+
+	for id := range retired {
+		log.Printf("Retired SLO %s (%s no longer reported)", id, parent.SLO.Spec.ExpandBy)
+	}
+
+	for _, child := range started {
+		if audit != nil {
+			if err := audit.StoreSLODefinition(child.SLO); err != nil {
+				log.Printf("Warning: failed to store SLO definition %s: %v", child.SLO.Metadata.ID, err)
+			}
+		}
+		log.Printf("Started SLO %s (%s=%s)", child.SLO.Metadata.ID, parent.SLO.Spec.ExpandBy, child.Vars[parent.SLO.Spec.ExpandBy])
+	}
+}
+
 // evaluateLoop runs periodic evaluations for a single SLO
 func (s *Scheduler) evaluateLoop(ctx context.Context, sloSpec *slo.SLO) {
 	defer s.wg.Done()
@@ -142,7 +389,7 @@ func (s *Scheduler) evaluateLoop(ctx context.Context, sloSpec *slo.SLO) {
 	}
 
 	// Initial evaluation
-	s.evaluateOnce(sloSpec, interval)
+	s.evaluateOnce(ctx, sloSpec, interval)
 
 	// Periodic evaluations
 	ticker := time.NewTicker(interval)
@@ -153,40 +400,72 @@ func (s *Scheduler) evaluateLoop(ctx context.Context, sloSpec *slo.SLO) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			s.evaluateOnce(sloSpec, interval)
+			s.evaluateOnce(ctx, sloSpec, interval)
 		}
 	}
 }
 
-// evaluateOnce performs a single evaluation of an SLO
-func (s *Scheduler) evaluateOnce(sloSpec *slo.SLO, interval time.Duration) {
+// evaluateOnce performs a single evaluation of an SLO. A failed evaluation is turned
+// into a result carrying the error, which the policy engine maps to a decision and
+// which is cached and audited like any other. If ctx is cancelled mid-evaluation,
+// in-flight queries are abandoned, nothing is cached or stored, and ctx.Err() is returned.
+func (s *Scheduler) evaluateOnce(ctx context.Context, sloSpec *slo.SLO, interval time.Duration) error {
 	now := time.Now()
 
 	// Evaluate SLO
-	evalResult, err := s.evaluator.Evaluate(sloSpec, now)
+	evalResult, err := s.evaluate(ctx, sloSpec, now)
 	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		log.Printf("Error evaluating SLO %s: %v", sloSpec.Metadata.ID, err)
-		return
+		evalResult = &eval.EvaluationResult{
+			SLOID:     sloSpec.Metadata.ID,
+			Timestamp: now,
+			Error:     err.Error(),
+		}
+	} else if len(evalResult.QueryErrors) > 0 {
+		log.Printf("Query errors evaluating SLO %s: %v", sloSpec.Metadata.ID, evalResult.QueryErrors)
 	}
 
-	// Apply policy
-	gateResult := s.policyEngine.Evaluate(sloSpec, evalResult)
+	s.mu.RLock()
+	audit := s.audit
+	s.mu.RUnlock()
+
+	// Refine the forecast with the budget trend of recent evaluations
+	if audit != nil && evalResult.Forecast != nil {
+		evalResult.Forecast = forecastWithHistory(audit, sloSpec, evalResult)
+	}
+
+	// Apply policy, cache the result and save rule states, unless the SLO was stopped or
+	// removed while it was evaluated. stopLocked cancels and drops state under s.mu, so
+	// checking under s.mu keeps a late evaluation from bringing a removed SLO back.
+	s.mu.RLock()
+	if err := ctx.Err(); err != nil {
+		s.mu.RUnlock()
+		return err
+	}
+	if !s.loadedLocked(sloSpec.Metadata.ID) {
+		s.mu.RUnlock()
+		return fmt.Errorf("SLO %s was removed during evaluation", sloSpec.Metadata.ID)
+	}
 
-	// Cache the result
+	// The cache keeps the decision without freezes, which are applied when it is served
+	healthResult := s.policyEngine.EvaluateHealth(sloSpec, evalResult)
+	gateResult := s.policyEngine.ApplyFreezes(sloSpec, healthResult, evalResult.Timestamp)
 	state := &EvaluationState{
 		EvalResult: evalResult,
-		GateResult: gateResult,
+		GateResult: healthResult,
 		UpdatedAt:  now,
 		TTL:        interval,
 	}
-
 	s.cache.Set(sloSpec.Metadata.ID, state)
-
-	// Persist to audit storage if available
-	s.mu.RLock()
-	audit := s.audit
+	if audit != nil {
+		s.saveRuleStates(audit, sloSpec)
+	}
 	s.mu.RUnlock()
 
+	// Persist to audit storage if available
 	if audit != nil {
 		// Store evaluation record
 		if err := audit.StoreEvaluation(evalResult, gateResult); err != nil {
@@ -201,6 +480,61 @@ func (s *Scheduler) evaluateOnce(sloSpec *slo.SLO, interval time.Duration) {
 
 	log.Printf("Evaluated SLO %s: decision=%s, SLI=%.4f",
 		sloSpec.Metadata.ID, gateResult.Decision, evalResult.SLI.Value)
+	return nil
+}
+
+// forecastWithHistory recomputes an evaluation's forecast from the budget remaining of
+// the live evaluations stored over the forecast window. The burn rate forecast is kept
+// when history cannot be read.
+func forecastWithHistory(audit storage.AuditStorage, sloSpec *slo.SLO, evalResult *eval.EvaluationResult) *eval.Forecast {
+	window, err := slo.ParseDuration(evalResult.Forecast.Window)
+	if err != nil {
+		return evalResult.Forecast
+	}
+
+	start := evalResult.Timestamp.Add(-window)
+	end := evalResult.Timestamp
+	live := false
+	records, err := audit.QueryAudit(storage.AuditFilter{
+		SLOID:      sloSpec.Metadata.ID,
+		Backfilled: &live,
+		StartTime:  &start,
+		EndTime:    &end,
+		Limit:      maxForecastHistory,
+	})
+	if err != nil {
+		log.Printf("Warning: failed to read budget history for SLO %s: %v", sloSpec.Metadata.ID, err)
+		return evalResult.Forecast
+	}
+
+	history := make([]eval.BudgetPoint, 0, len(records))
+	for _, record := range records {
+		if record.QueryFailed {
+			continue
+		}
+		history = append(history, eval.BudgetPoint{
+			Timestamp:       record.Timestamp,
+			BudgetRemaining: record.BudgetRemaining,
+		})
+	}
+
+	return eval.ComputeForecast(sloSpec, evalResult, history)
+}
+
+// evaluate runs the evaluator for an SLO, resolving components of composite SLOs
+func (s *Scheduler) evaluate(ctx context.Context, sloSpec *slo.SLO, now time.Time) (*eval.EvaluationResult, error) {
+	if !sloSpec.IsComposite() {
+		return s.evaluator.Evaluate(ctx, sloSpec, now)
+	}
+
+	s.mu.RLock()
+	components := make(map[string]*slo.SLO, len(s.slos))
+	for _, sloWithFile := range s.slos {
+		components[sloWithFile.SLO.Metadata.ID] = sloWithFile.SLO
+	}
+	s.mu.RUnlock()
+
+	return s.evaluator.EvaluateComposite(ctx, sloSpec, components, now)
 }
 
 // GetCache returns the state cache
@@ -208,6 +542,26 @@ func (s *Scheduler) GetCache() *StateCache {
 	return s.cache
 }
 
+// QueryStats returns the metrics adapter's query cache counters, if it keeps any
+func (s *Scheduler) QueryStats() (eval.QueryStats, bool) {
+	return s.evaluator.QueryStats()
+}
+
+// FreezeCalendar returns the policy engine's change freezes, if any
+func (s *Scheduler) FreezeCalendar() *policy.FreezeCalendar {
+	return s.policyEngine.FreezeCalendar()
+}
+
+// ApplyFreezes returns the decision of a cached state with the change freezes active at
+// the given time applied
+func (s *Scheduler) ApplyFreezes(sloID string, result *policy.GateResult, at time.Time) *policy.GateResult {
+	sloSpec := s.findSLO(sloID)
+	if sloSpec == nil {
+		return result
+	}
+	return s.policyEngine.ApplyFreezes(sloSpec, result, at)
+}
+
 // GetAuditStorage returns the audit storage backend
 func (s *Scheduler) GetAuditStorage() storage.AuditStorage {
 	s.mu.RLock()
@@ -215,7 +569,7 @@ func (s *Scheduler) GetAuditStorage() storage.AuditStorage {
 	return s.audit
 }
 
-// GetSLOs returns the loaded SLOs
+// GetSLOs returns the loaded SLOs followed by the children discovered for expandBy SLOs
 func (s *Scheduler) GetSLOs() []slo.SLOWithFile {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -223,6 +577,19 @@ func (s *Scheduler) GetSLOs() []slo.SLOWithFile {
 	// Return a copy
 	result := make([]slo.SLOWithFile, len(s.slos))
 	copy(result, s.slos)
+
+	for _, sloWithFile := range s.slos {
+		children := s.children[sloWithFile.SLO.Metadata.ID]
+		ids := make([]string, 0, len(children))
+		for id := range children {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			result = append(result, children[id].SLOWithFile)
+		}
+	}
+
 	return result
 }
 
@@ -233,27 +600,45 @@ func (s *Scheduler) SetSLOsForTest(slos []slo.SLOWithFile) {
 	s.slos = slos
 }
 
-// EvaluateNow forces immediate evaluation of a specific SLO
-func (s *Scheduler) EvaluateNow(sloID string) error {
-	s.mu.RLock()
-	var targetSLO *slo.SLO
-	for _, sloWithFile := range s.slos {
-		if sloWithFile.SLO.Metadata.ID == sloID {
-			targetSLO = sloWithFile.SLO
-			break
-		}
-	}
-	s.mu.RUnlock()
-
+// EvaluateNow forces immediate evaluation of a specific SLO.
+// Cancelling ctx (e.g. the HTTP client disconnecting) abandons in-flight queries.
+func (s *Scheduler) EvaluateNow(ctx context.Context, sloID string) error {
+	targetSLO := s.findSLO(sloID)
 	if targetSLO == nil {
 		return fmt.Errorf("SLO not found: %s", sloID)
 	}
 
+	if targetSLO.IsDynamic() {
+		return fmt.Errorf("SLO %s uses expandBy; evaluate one of its children instead", sloID)
+	}
+
 	interval, err := slo.ParseDuration(targetSLO.Spec.EvaluationInterval)
 	if err != nil {
 		return fmt.Errorf("invalid evaluation interval: %w", err)
 	}
 
-	s.evaluateOnce(targetSLO, interval)
+	return s.evaluateOnce(ctx, targetSLO, interval)
+}
+
+// loadedLocked reports whether an SLO or discovered child is loaded. s.mu must be held.
+func (s *Scheduler) loadedLocked(sloID string) bool {
+	for _, sloWithFile := range s.slos {
+		if sloWithFile.SLO.Metadata.ID == sloID {
+			return true
+		}
+		if _, ok := s.children[sloWithFile.SLO.Metadata.ID][sloID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// findSLO returns a loaded SLO or discovered child by ID, or nil
+func (s *Scheduler) findSLO(sloID string) *slo.SLO {
+	for _, sloWithFile := range s.GetSLOs() {
+		if sloWithFile.SLO.Metadata.ID == sloID {
+			return sloWithFile.SLO
+		}
+	}
 	return nil
 }
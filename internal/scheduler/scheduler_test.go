@@ -0,0 +1,214 @@
+package scheduler
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/adapter/synthetic"
+	"github.com/samijaber1/aegis-slo/internal/eval"
+	"github.com/samijaber1/aegis-slo/internal/policy"
+	"github.com/samijaber1/aegis-slo/internal/slo"
+	"github.com/samijaber1/aegis-slo/internal/storage/sqlite"
+)
+
+func TestScheduler_DiscoverChildren(t *testing.T) {
+	adapter := synthetic.NewAdapter()
+	for _, customer := range []string{"acme", "globex"} {
+		windows := map[string]synthetic.WindowData{}
+		for _, window := range []string{"5m", "1h", "30d"} {
+			windows[window] = synthetic.WindowData{Good: 999, Total: 1000}
+		}
+		adapter.SetFixture(customer, &synthetic.MetricFixture{Windows: windows})
+	}
+	adapter.SetLabelValues("customer", []string{"acme", "globex"})
+
+	sched := NewScheduler(eval.NewEvaluator(adapter), policy.NewEngine(), "")
+
+	parent := slo.SLOWithFile{
+		File: "customers.yaml",
+		SLO: &slo.SLO{
+			Kind:     slo.KindSLO,
+			Metadata: slo.Metadata{ID: "api-customer", Service: "api"},
+			Spec: slo.Spec{
+				Objective:          0.99,
+				ComplianceWindow:   "30d",
+				EvaluationInterval: "1h",
+				ExpandBy:           "customer",
+				SLI: slo.SLI{
+					Type:  "ratio",
+					Good:  slo.QueryRef{PrometheusQuery: "{{customer}}"},
+					Total: slo.QueryRef{PrometheusQuery: "{{customer}}"},
+				},
+				BurnPolicy: slo.BurnPolicy{Rules: []slo.BurnRule{
+					{Name: "fast", ShortWindow: "5m", LongWindow: "1h", Threshold: 14, Action: "BLOCK"},
+				}},
+				Gating: slo.Gating{StalenessLimit: "120s"},
+			},
+		},
+	}
+	sched.SetSLOsForTest([]slo.SLOWithFile{parent})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		sched.wg.Wait()
+	}()
+
+	sched.discoverChildren(ctx, parent)
+
+	slos := sched.GetSLOs()
+	if len(slos) != 3 {
+		t.Fatalf("expected parent and 2 children, got %d", len(slos))
+	}
+	if slos[1].SLO.Metadata.ID != "api-customer-acme" || slos[1].Parent != "api-customer" {
+		t.Errorf("unexpected first child %s (parent %q)", slos[1].SLO.Metadata.ID, slos[1].Parent)
+	}
+
+	waitForCache(t, sched, "api-customer-acme")
+	waitForCache(t, sched, "api-customer-globex")
+
+	// globex disappears: its child is retired and its cached state dropped
+	adapter.SetLabelValues("customer", []string{"acme"})
+	sched.discoverChildren(ctx, parent)
+
+	slos = sched.GetSLOs()
+	if len(slos) != 2 {
+		t.Fatalf("expected parent and 1 child after retirement, got %d", len(slos))
+	}
+	if _, ok := sched.GetCache().Get("api-customer-globex"); ok {
+		t.Error("expected retired child to be removed from cache")
+	}
+	if _, ok := sched.GetCache().Get("api-customer-acme"); !ok {
+		t.Error("expected remaining child to keep its cached state")
+	}
+
+	// Discovery errors keep the existing children
+	adapter.SetLabelValues("customer", nil)
+	sched.discoverChildren(ctx, slo.SLOWithFile{SLO: &slo.SLO{
+		Metadata: slo.Metadata{ID: "api-customer"},
+		Spec:     slo.Spec{ExpandBy: "tenant"},
+	}})
+	if len(sched.GetSLOs()) != 2 {
+		t.Error("expected children to survive a failed discovery")
+	}
+}
+
+func TestScheduler_StopDropsRuleStates(t *testing.T) {
+	store, err := sqlite.NewStore(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	engine := policy.NewEngine()
+	sched := NewScheduler(eval.NewEvaluator(synthetic.NewAdapter()), engine, "")
+	sched.SetAuditStorage(store)
+
+	firingSince := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	states := []policy.RuleState{
+		{SLOID: "api-customer", Rule: "fast", FiringSince: firingSince},
+		{SLOID: "api-customer-acme", Rule: "fast", FiringSince: firingSince},
+		{SLOID: "checkout", Rule: "fast", FiringSince: firingSince},
+	}
+	engine.RestoreRuleStates(states)
+	for _, state := range states {
+		if err := store.SaveRuleStates(state.SLOID, []policy.RuleState{state}); err != nil {
+			t.Fatalf("failed to save rule states: %v", err)
+		}
+	}
+
+	// Removing the expandBy parent drops its own and its children's rule states
+	sched.mu.Lock()
+	sched.children["api-customer"] = map[string]*dynamicChild{"api-customer-acme": {cancel: func() {}}}
+	sched.stopLocked("api-customer", true)
+	sched.mu.Unlock()
+
+	for _, id := range []string{"api-customer", "api-customer-acme"} {
+		if remaining := engine.RuleStates(id); len(remaining) != 0 {
+			t.Errorf("expected no engine state for %s, got %+v", id, remaining)
+		}
+	}
+	if remaining := engine.RuleStates("checkout"); len(remaining) != 1 {
+		t.Errorf("expected the state of checkout to be kept, got %+v", remaining)
+	}
+
+	stored, err := store.LoadRuleStates()
+	if err != nil {
+		t.Fatalf("failed to load rule states: %v", err)
+	}
+	if len(stored) != 1 || stored[0].SLOID != "checkout" {
+		t.Errorf("expected only the stored state of checkout, got %+v", stored)
+	}
+}
+
+func waitForCache(t *testing.T, sched *Scheduler, sloID string) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, ok := sched.GetCache().Get(sloID); ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s to be evaluated", sloID)
+}
+
+// blockingAdapter answers every ratio query once release is closed, ignoring ctx
+type blockingAdapter struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (a *blockingAdapter) QueryRatio(ctx context.Context, query eval.RatioQuery) (eval.RatioResult, error) {
+	select {
+	case a.started <- struct{}{}:
+	default:
+	}
+	<-a.release
+	return eval.RatioResult{Window: query.Window, Good: eval.SeriesValue{Value: 999}, Total: eval.SeriesValue{Value: 1000}}, nil
+}
+
+func TestScheduler_LateEvaluationOfStoppedSLO(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(sched *Scheduler, cancel context.CancelFunc)
+	}{
+		{
+			name: "runner cancelled",
+			stop: func(sched *Scheduler, cancel context.CancelFunc) { cancel() },
+		},
+		{
+			name: "SLO removed",
+			stop: func(sched *Scheduler, cancel context.CancelFunc) { sched.SetSLOsForTest(nil) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := &blockingAdapter{started: make(chan struct{}, 1), release: make(chan struct{})}
+			sched := NewScheduler(eval.NewEvaluator(adapter), policy.NewEngine(), "")
+			sloSpec := auditTestSLO()
+			sched.SetSLOsForTest([]slo.SLOWithFile{{SLO: sloSpec, File: "api-availability.yaml"}})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			done := make(chan error, 1)
+			go func() { done <- sched.evaluateOnce(ctx, sloSpec, time.Minute) }()
+
+			// Stop the SLO while its evaluation is in flight, then let it finish
+			<-adapter.started
+			tt.stop(sched, cancel)
+			close(adapter.release)
+
+			if err := <-done; err == nil {
+				t.Error("expected the late evaluation to be discarded")
+			}
+			if _, ok := sched.GetCache().Get(sloSpec.Metadata.ID); ok {
+				t.Error("expected no cached state for the stopped SLO")
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package slo
+
+import (
+	"fmt"
+	"time"
+
+	// Embed the timezone database so calendar windows resolve on minimal images
+	_ "time/tzdata"
+)
+
+// CalendarWindow is a compliance window aligned to calendar periods.
+// The error budget resets at each period boundary in the configured timezone.
+type CalendarWindow struct {
+	Calendar string `yaml:"calendar"`           // week, month or quarter
+	Timezone string `yaml:"timezone,omitempty"` // IANA name, defaults to UTC
+}
+
+// Location resolves the configured timezone
+func (c CalendarWindow) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", c.Timezone, err)
+	}
+	return loc, nil
+}
+
+// Period returns the start and end of the calendar period containing t.
+// Weeks start on Monday.
+func (c CalendarWindow) Period(t time.Time) (start, end time.Time, err error) {
+	loc, err := c.Location()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	local := t.In(loc)
+	year, month, day := local.Date()
+
+	switch c.Calendar {
+	case "week":
+		offset := (int(local.Weekday()) + 6) % 7 // days since Monday
+		start = time.Date(year, month, day-offset, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 0, 7)
+	case "month":
+		start = time.Date(year, month, 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 1, 0)
+	case "quarter":
+		firstMonth := time.Month((int(month)-1)/3*3 + 1)
+		start = time.Date(year, firstMonth, 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 3, 0)
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("unknown calendar period: %s", c.Calendar)
+	}
+
+	return start, end, nil
+}
+
+// MinLength returns the shortest possible length of the calendar period
+func (c CalendarWindow) MinLength() (time.Duration, error) {
+	switch c.Calendar {
+	case "week":
+		return 7 * 24 * time.Hour, nil
+	case "month":
+		return 28 * 24 * time.Hour, nil
+	case "quarter":
+		return 89 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unknown calendar period: %s", c.Calendar)
+	}
+}
+
+// String returns a compact label such as "calendar:month(Europe/Berlin)"
+func (c CalendarWindow) String() string {
+	tz := c.Timezone
+	if tz == "" {
+		tz = "UTC"
+	}
+	return fmt.Sprintf("calendar:%s(%s)", c.Calendar, tz)
+}
+
+// ComplianceWindowName returns the rolling compliance window, or the calendar
+// window label when the SLO uses a calendar-aligned window
+func (s Spec) ComplianceWindowName() string {
+	if s.CalendarWindow != nil {
+		return s.CalendarWindow.String()
+	}
+	return s.ComplianceWindow
+}
@@ -0,0 +1,90 @@
+package slo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendarWindow_Period(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		window        CalendarWindow
+		at            time.Time
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "month in UTC",
+			window:        CalendarWindow{Calendar: "month"},
+			at:            time.Date(2024, 2, 15, 12, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "month boundary respects timezone",
+			window:        CalendarWindow{Calendar: "month", Timezone: "Europe/Berlin"},
+			at:            time.Date(2024, 2, 29, 23, 30, 0, 0, time.UTC), // already March 1st in Berlin
+			expectedStart: time.Date(2024, 3, 1, 0, 0, 0, 0, berlin),
+			expectedEnd:   time.Date(2024, 4, 1, 0, 0, 0, 0, berlin),
+		},
+		{
+			name:          "week starts on Monday",
+			window:        CalendarWindow{Calendar: "week"},
+			at:            time.Date(2024, 1, 14, 8, 0, 0, 0, time.UTC), // Sunday
+			expectedStart: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "quarter",
+			window:        CalendarWindow{Calendar: "quarter"},
+			at:            time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := tt.window.Period(tt.at)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !start.Equal(tt.expectedStart) {
+				t.Errorf("expected start %v, got %v", tt.expectedStart, start)
+			}
+			if !end.Equal(tt.expectedEnd) {
+				t.Errorf("expected end %v, got %v", tt.expectedEnd, end)
+			}
+		})
+	}
+}
+
+func TestCalendarWindow_InvalidTimezone(t *testing.T) {
+	window := CalendarWindow{Calendar: "month", Timezone: "Mars/Olympus"}
+	if _, _, err := window.Period(time.Now()); err == nil {
+		t.Error("expected error for invalid timezone")
+	}
+}
+
+func TestValidateComplianceWindow_Calendar(t *testing.T) {
+	slo := &SLO{
+		Spec: Spec{
+			CalendarWindow: &CalendarWindow{Calendar: "week"},
+			BurnPolicy: BurnPolicy{
+				Rules: []BurnRule{
+					{ShortWindow: "6h", LongWindow: "10d"},
+				},
+			},
+		},
+	}
+
+	errors := validateComplianceWindow("test.yaml", slo)
+	if len(errors) != 1 {
+		t.Fatalf("expected 10d window to exceed a weekly calendar window, got %v", errors)
+	}
+}
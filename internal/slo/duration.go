@@ -35,3 +35,17 @@ func ParseDuration(s string) (time.Duration, error) {
 		return 0, fmt.Errorf("unknown duration unit: %s", unit)
 	}
 }
+
+// FormatDuration converts a time.Duration back to a duration string
+func FormatDuration(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+	if d%time.Minute == 0 {
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return fmt.Sprintf("%ds", d/time.Second)
+}
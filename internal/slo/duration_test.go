@@ -72,9 +72,9 @@ func TestFormatDuration(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.want, func(t *testing.T) {
-			got := formatDuration(tt.input)
+			got := FormatDuration(tt.input)
 			if got != tt.want {
-				t.Errorf("formatDuration(%v) = %q, want %q", tt.input, got, tt.want)
+				t.Errorf("FormatDuration(%v) = %q, want %q", tt.input, got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,80 @@
+package slo
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// invalidIDChars matches characters not allowed in SLO IDs
+var invalidIDChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// promStringEscaper escapes label values for use inside PromQL string literals
+var promStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// IsDynamic reports whether the SLO is a template expanded per label value
+func (s *SLO) IsDynamic() bool {
+	return s.Spec.ExpandBy != ""
+}
+
+// ExpandLabelValues generates one child SLO per label value of a dynamic SLO.
+// The value is substituted for the {{<expandBy>}} placeholder and the child ID is
+// the parent ID followed by the value, made ID-safe. Values that map to the same
+// ID after sanitising get a hash suffix so children stay distinct.
+func ExpandLabelValues(parent SLOWithFile, values []string) ([]SLOWithFile, error) {
+	label := parent.SLO.Spec.ExpandBy
+	if label == "" {
+		return nil, fmt.Errorf("SLO %s does not declare expandBy", parent.SLO.Metadata.ID)
+	}
+
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+
+	children := make([]SLOWithFile, 0, len(sorted))
+	seen := make(map[string]bool, len(sorted))
+	for _, value := range sorted {
+		if value == "" {
+			continue
+		}
+
+		child, err := expandSLO(parent.SLO, map[string]string{label: promStringEscaper.Replace(value)})
+		if err != nil {
+			return nil, fmt.Errorf("expand %s=%s: %w", label, value, err)
+		}
+		child.Spec.ExpandBy = ""
+		child.Spec.ExpandMatch = ""
+		child.Metadata.ID = parent.SLO.Metadata.ID + "-" + sanitizeIDPart(value)
+		if seen[child.Metadata.ID] {
+			h := fnv.New32a()
+			h.Write([]byte(value))
+			child.Metadata.ID = fmt.Sprintf("%s-%08x", child.Metadata.ID, h.Sum32())
+		}
+		seen[child.Metadata.ID] = true
+
+		vars := make(map[string]string, len(parent.Vars)+1)
+		for k, v := range parent.Vars {
+			vars[k] = v
+		}
+		vars[label] = value
+
+		children = append(children, SLOWithFile{
+			SLO:    child,
+			File:   parent.File,
+			Vars:   vars,
+			Parent: parent.SLO.Metadata.ID,
+		})
+	}
+
+	return children, nil
+}
+
+// sanitizeIDPart replaces characters not allowed in SLO IDs with dashes
+func sanitizeIDPart(value string) string {
+	sanitized := strings.Trim(invalidIDChars.ReplaceAllString(value, "-"), "-")
+	if sanitized == "" {
+		return "empty"
+	}
+	return sanitized
+}
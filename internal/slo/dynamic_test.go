@@ -0,0 +1,83 @@
+package slo
+
+import (
+	"testing"
+)
+
+func TestExpandLabelValues(t *testing.T) {
+	parent := SLOWithFile{
+		File: "routes.yaml",
+		SLO: &SLO{
+			Metadata: Metadata{ID: "api-route"},
+			Spec: Spec{
+				ExpandBy:    "route",
+				ExpandMatch: `http_requests_total{job="api"}`,
+				SLI: SLI{
+					Type:  "ratio",
+					Good:  QueryRef{PrometheusQuery: `good{route="{{route}}"}[{{window}}]`},
+					Total: QueryRef{PrometheusQuery: `total{route="{{route}}"}[{{window}}]`},
+				},
+			},
+		},
+	}
+
+	children, err := ExpandLabelValues(parent, []string{"/checkout", "/cart", "", "checkout"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(children) != 3 {
+		t.Fatalf("expected 3 children, got %d", len(children))
+	}
+
+	byValue := make(map[string]SLOWithFile)
+	ids := make(map[string]bool)
+	for _, child := range children {
+		byValue[child.Vars["route"]] = child
+		if ids[child.SLO.Metadata.ID] {
+			t.Errorf("duplicate child ID %s", child.SLO.Metadata.ID)
+		}
+		ids[child.SLO.Metadata.ID] = true
+	}
+
+	cart := byValue["/cart"]
+	if cart.SLO.Metadata.ID != "api-route-cart" {
+		t.Errorf("expected ID api-route-cart, got %s", cart.SLO.Metadata.ID)
+	}
+	if want := `good{route="/cart"}[{{window}}]`; cart.SLO.Spec.SLI.Good.PrometheusQuery != want {
+		t.Errorf("expected good query %q, got %q", want, cart.SLO.Spec.SLI.Good.PrometheusQuery)
+	}
+	if cart.Parent != "api-route" || cart.File != "routes.yaml" {
+		t.Errorf("expected parent and file to be kept, got %q %q", cart.Parent, cart.File)
+	}
+	if cart.SLO.IsDynamic() {
+		t.Error("expected children not to be dynamic")
+	}
+
+	// "/checkout" and "checkout" sanitise to the same ID; the later one gets a hash suffix
+	if byValue["/checkout"].SLO.Metadata.ID != "api-route-checkout" {
+		t.Errorf("expected api-route-checkout, got %s", byValue["/checkout"].SLO.Metadata.ID)
+	}
+	if byValue["checkout"].SLO.Metadata.ID == "api-route-checkout" {
+		t.Error("expected colliding value to get a distinct ID")
+	}
+}
+
+func TestExpandLabelValues_EscapesQuotes(t *testing.T) {
+	parent := SLOWithFile{
+		SLO: &SLO{
+			Metadata: Metadata{ID: "tenant"},
+			Spec: Spec{
+				ExpandBy: "tenant",
+				SLI:      SLI{Good: QueryRef{PrometheusQuery: `good{tenant="{{tenant}}"}`}},
+			},
+		},
+	}
+
+	children, err := ExpandLabelValues(parent, []string{`a"b`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `good{tenant="a\"b"}`; children[0].SLO.Spec.SLI.Good.PrometheusQuery != want {
+		t.Errorf("expected %q, got %q", want, children[0].SLO.Spec.SLI.Good.PrometheusQuery)
+	}
+}
@@ -8,8 +8,19 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-// LoadFromDirectory discovers and loads all SLO files from a directory
+// LoadFromDirectory discovers and loads all SLO files from a directory,
+// expanding matrix specs into concrete SLOs
 func LoadFromDirectory(dirPath string) ([]SLOWithFile, []ValidationError) {
+	raw, errors := loadRawFromDirectory(dirPath)
+
+	slos, expandErrors := ExpandMatrix(raw)
+	errors = append(errors, expandErrors...)
+
+	return slos, errors
+}
+
+// loadRawFromDirectory discovers and parses all SLO files from a directory as written
+func loadRawFromDirectory(dirPath string) ([]SLOWithFile, []ValidationError) {
 	var slos []SLOWithFile
 	var errors []ValidationError
 
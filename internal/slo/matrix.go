@@ -0,0 +1,204 @@
+package slo
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// placeholderPattern matches {{name}} query placeholders
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+// ExpandMatrix expands SLOs that declare spec.matrix into one concrete SLO per
+// combination of variable values. {{name}} placeholders are substituted in queries,
+// the environment, the description and composite component IDs; {{window}} is left
+// for the metrics adapter. Expanded IDs are the source ID followed by the values in
+// variable-name order, e.g. checkout-availability-prod-eu.
+// Expanded SLOs keep the source file so errors are reported against it.
+func ExpandMatrix(slos []SLOWithFile) ([]SLOWithFile, []ValidationError) {
+	var expanded []SLOWithFile
+	var errors []ValidationError
+
+	for _, sloWithFile := range slos {
+		if len(sloWithFile.SLO.Spec.Matrix) == 0 {
+			expanded = append(expanded, sloWithFile)
+			errors = append(errors, checkPlaceholders(sloWithFile.File, sloWithFile.SLO)...)
+			continue
+		}
+
+		for i, vars := range matrixCombinations(sloWithFile.SLO.Spec.Matrix) {
+			concrete, err := expandSLO(sloWithFile.SLO, vars)
+			if err != nil {
+				errors = append(errors, ValidationError{
+					File:    sloWithFile.File,
+					Path:    "spec.matrix",
+					Message: fmt.Sprintf("failed to expand %s: %v", formatVars(vars), err),
+				})
+				continue
+			}
+
+			// Leftover placeholders are identical in every combination; report them once
+			if i == 0 {
+				errors = append(errors, checkPlaceholders(sloWithFile.File, concrete)...)
+			}
+			expanded = append(expanded, SLOWithFile{
+				SLO:  concrete,
+				File: sloWithFile.File,
+				Vars: vars,
+			})
+		}
+	}
+
+	return expanded, errors
+}
+
+// matrixCombinations returns the cartesian product of the matrix variables
+func matrixCombinations(matrix map[string][]string) []map[string]string {
+	names := make([]string, 0, len(matrix))
+	for name := range matrix {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	combinations := []map[string]string{{}}
+	for _, name := range names {
+		var next []map[string]string
+		for _, combination := range combinations {
+			for _, value := range matrix[name] {
+				vars := make(map[string]string, len(combination)+1)
+				for k, v := range combination {
+					vars[k] = v
+				}
+				vars[name] = value
+				next = append(next, vars)
+			}
+		}
+		combinations = next
+	}
+
+	return combinations
+}
+
+// expandSLO returns a deep copy of the SLO with variables substituted
+func expandSLO(source *SLO, vars map[string]string) (*SLO, error) {
+	concrete, err := cloneSLO(source)
+	if err != nil {
+		return nil, err
+	}
+	concrete.Spec.Matrix = nil
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	idParts := []string{source.Metadata.ID}
+	for _, name := range names {
+		idParts = append(idParts, sanitizeIDPart(vars[name]))
+	}
+	concrete.Metadata.ID = strings.Join(idParts, "-")
+	if len(concrete.Metadata.ID) > 128 {
+		return nil, fmt.Errorf("expanded ID %q exceeds 128 characters", concrete.Metadata.ID)
+	}
+
+	for _, field := range templatedFields(concrete) {
+		*field.value = substituteVars(*field.value, vars)
+	}
+
+	return concrete, nil
+}
+
+// cloneSLO deep-copies an SLO through its YAML representation
+func cloneSLO(source *SLO) (*SLO, error) {
+	data, err := yaml.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+	var clone SLO
+	if err := yaml.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
+// templatedField is a field that may contain variable placeholders
+type templatedField struct {
+	path  string
+	value *string
+}
+
+// templatedFields returns every field that may contain variable placeholders
+func templatedFields(s *SLO) []templatedField {
+	fields := []templatedField{
+		{"metadata.description", &s.Metadata.Description},
+		{"spec.environment", &s.Spec.Environment},
+		{"spec.sli.good.prometheusQuery", &s.Spec.SLI.Good.PrometheusQuery},
+		{"spec.sli.total.prometheusQuery", &s.Spec.SLI.Total.PrometheusQuery},
+	}
+	if s.Spec.SLI.TimeSlice != nil {
+		fields = append(fields, templatedField{"spec.sli.timeSlice.query.prometheusQuery", &s.Spec.SLI.TimeSlice.Query.PrometheusQuery})
+	}
+	for i := range s.Spec.SLI.Targets {
+		fields = append(fields, templatedField{fmt.Sprintf("spec.sli.targets[%d].good.prometheusQuery", i), &s.Spec.SLI.Targets[i].Good.PrometheusQuery})
+	}
+	if s.Spec.Composite != nil {
+		for i := range s.Spec.Composite.Components {
+			fields = append(fields, templatedField{fmt.Sprintf("spec.composite.components[%d].sloID", i), &s.Spec.Composite.Components[i].SLOID})
+		}
+	}
+	return fields
+}
+
+// substituteVars replaces {{name}} placeholders with variable values, leaving unknown ones intact
+func substituteVars(s string, vars map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// checkPlaceholders reports placeholders left after expansion, other than {{window}}
+// and the expandBy label which is substituted at discovery time
+func checkPlaceholders(file string, s *SLO) []ValidationError {
+	var errors []ValidationError
+
+	seen := make(map[string]bool)
+	for _, field := range templatedFields(s) {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(*field.value, -1) {
+			key := field.path + match[1]
+			if match[1] == "window" || match[1] == s.Spec.ExpandBy || seen[key] {
+				continue
+			}
+			seen[key] = true
+			errors = append(errors, ValidationError{
+				File:    file,
+				Path:    field.path,
+				Message: fmt.Sprintf("unresolved placeholder %s in %s (declare it in spec.matrix)", match[0], s.Metadata.ID),
+			})
+		}
+	}
+
+	return errors
+}
+
+// formatVars renders variables as "env=prod,region=eu" in name order
+func formatVars(vars map[string]string) string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + "=" + vars[name]
+	}
+	return strings.Join(parts, ",")
+}
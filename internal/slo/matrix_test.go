@@ -0,0 +1,98 @@
+package slo
+
+import (
+	"testing"
+)
+
+func TestExpandMatrix(t *testing.T) {
+	source := &SLO{
+		Kind:     KindSLO,
+		Metadata: Metadata{ID: "api-availability", Description: "API in {{env}}/{{region}}"},
+		Spec: Spec{
+			Environment: "{{env}}",
+			Matrix: Matrix{
+				"region": {"us", "eu"},
+				"env":    {"staging", "prod"},
+			},
+			SLI: SLI{
+				Type:  "ratio",
+				Good:  QueryRef{PrometheusQuery: `good{env="{{env}}",region="{{ region }}"}[{{window}}]`},
+				Total: QueryRef{PrometheusQuery: `total{env="{{env}}",region="{{region}}"}[{{window}}]`},
+			},
+		},
+	}
+
+	expanded, errors := ExpandMatrix([]SLOWithFile{{SLO: source, File: "api.yaml"}})
+	if len(errors) != 0 {
+		t.Fatalf("unexpected errors: %v", errors)
+	}
+	if len(expanded) != 4 {
+		t.Fatalf("expected 4 expanded SLOs, got %d", len(expanded))
+	}
+
+	byID := make(map[string]SLOWithFile)
+	for _, e := range expanded {
+		byID[e.SLO.Metadata.ID] = e
+	}
+
+	prodEU, ok := byID["api-availability-prod-eu"]
+	if !ok {
+		t.Fatalf("expected api-availability-prod-eu, got %v", byID)
+	}
+	if prodEU.File != "api.yaml" {
+		t.Errorf("expected source file to be kept, got %s", prodEU.File)
+	}
+	if prodEU.SLO.Spec.Environment != "prod" {
+		t.Errorf("expected environment=prod, got %s", prodEU.SLO.Spec.Environment)
+	}
+	if want := `good{env="prod",region="eu"}[{{window}}]`; prodEU.SLO.Spec.SLI.Good.PrometheusQuery != want {
+		t.Errorf("expected good query %q, got %q", want, prodEU.SLO.Spec.SLI.Good.PrometheusQuery)
+	}
+	if prodEU.SLO.Metadata.Description != "API in prod/eu" {
+		t.Errorf("unexpected description %q", prodEU.SLO.Metadata.Description)
+	}
+	if prodEU.SLO.Spec.Matrix != nil {
+		t.Error("expected matrix to be cleared on expanded SLOs")
+	}
+	if prodEU.Vars["region"] != "eu" {
+		t.Errorf("expected vars to be recorded, got %v", prodEU.Vars)
+	}
+
+	// The source SLO is left untouched
+	if source.Spec.Environment != "{{env}}" {
+		t.Errorf("source SLO was modified: %s", source.Spec.Environment)
+	}
+}
+
+func TestExpandMatrix_UnresolvedPlaceholder(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix Matrix
+	}{
+		{name: "no matrix", matrix: nil},
+		{name: "undeclared variable", matrix: Matrix{"env": {"staging", "prod"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &SLO{
+				Metadata: Metadata{ID: "orders"},
+				Spec: Spec{
+					Matrix: tt.matrix,
+					SLI: SLI{
+						Good:  QueryRef{PrometheusQuery: `good{region="{{region}}"}[{{window}}]`},
+						Total: QueryRef{PrometheusQuery: `total[{{window}}]`},
+					},
+				},
+			}
+
+			_, errors := ExpandMatrix([]SLOWithFile{{SLO: source, File: "orders.yaml"}})
+			if len(errors) != 1 {
+				t.Fatalf("expected 1 error, got %d: %v", len(errors), errors)
+			}
+			if errors[0].Path != "spec.sli.good.prometheusQuery" {
+				t.Errorf("expected error on good query, got %s", errors[0].Path)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package slo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source provides SLO definitions as written, before matrix expansion
+type Source interface {
+	// Name identifies the source in logs and validation errors
+	Name() string
+	// Load returns the SLOs currently defined by the source
+	Load() ([]SLOWithFile, []ValidationError)
+}
+
+// LoadSources loads SLOs from every source, tagging each with its source name.
+// The result is not matrix-expanded; see ExpandMatrix and Validator.ValidateSLOs.
+func LoadSources(sources []Source) ([]SLOWithFile, []ValidationError) {
+	var slos []SLOWithFile
+	var errors []ValidationError
+
+	for _, source := range sources {
+		loaded, loadErrors := source.Load()
+		for i := range loaded {
+			loaded[i].Source = source.Name()
+		}
+		slos = append(slos, loaded...)
+		errors = append(errors, loadErrors...)
+	}
+
+	return slos, errors
+}
+
+// ParseSource builds a source from a command-line spec:
+//
+//	/path/to/dir or dir:/path     every *.yaml/*.yml file under a directory
+//	/path/to/slo.yaml or file:... a single file, read as is even if its name has glob characters
+//	glob:/etc/slos/*-prod.yaml    files matching a glob pattern
+//	https://config.internal/slos  a YAML document stream over HTTP(S)
+//	git:/srv/config@v1.4.0:slos   files under a path of a local git worktree at a ref
+func ParseSource(spec string) (Source, error) {
+	switch {
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return NewHTTPSource(spec), nil
+
+	case strings.HasPrefix(spec, "git:"):
+		rest := strings.TrimPrefix(spec, "git:")
+		at := strings.LastIndex(rest, "@")
+		if at <= 0 || at == len(rest)-1 {
+			return nil, fmt.Errorf("invalid git source %q: expected git:<worktree>@<ref>[:<path>]", spec)
+		}
+		ref, dir, _ := strings.Cut(rest[at+1:], ":")
+		return &GitSource{Worktree: rest[:at], Ref: ref, Path: dir}, nil
+
+	case strings.HasPrefix(spec, "glob:"):
+		pattern := strings.TrimPrefix(spec, "glob:")
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+		return &GlobSource{Pattern: pattern}, nil
+
+	case strings.HasPrefix(spec, "dir:"):
+		return &DirectorySource{Path: strings.TrimPrefix(spec, "dir:")}, nil
+
+	case strings.HasPrefix(spec, "file:"):
+		return &FileSource{Path: strings.TrimPrefix(spec, "file:")}, nil
+	}
+
+	info, err := os.Stat(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SLO source %q: %w", spec, err)
+	}
+	if !info.IsDir() {
+		return &FileSource{Path: spec}, nil
+	}
+	return &DirectorySource{Path: spec}, nil
+}
+
+// DirectorySource loads every *.yaml and *.yml file under a directory
+type DirectorySource struct {
+	Path string
+}
+
+// Name implements Source
+func (s *DirectorySource) Name() string {
+	return "dir:" + s.Path
+}
+
+// Load implements Source
+func (s *DirectorySource) Load() ([]SLOWithFile, []ValidationError) {
+	return loadRawFromDirectory(s.Path)
+}
+
+// FileSource loads a single file
+type FileSource struct {
+	Path string
+}
+
+// Name implements Source
+func (s *FileSource) Name() string {
+	return "file:" + s.Path
+}
+
+// Load implements Source
+func (s *FileSource) Load() ([]SLOWithFile, []ValidationError) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, []ValidationError{{
+			File:    s.Path,
+			Message: fmt.Sprintf("failed to read file: %v", err),
+		}}
+	}
+	return parseYAMLDocuments(s.Path, data)
+}
+
+// GlobSource loads the files matching a filepath.Glob pattern
+type GlobSource struct {
+	Pattern string
+}
+
+// Name implements Source
+func (s *GlobSource) Name() string {
+	return "glob:" + s.Pattern
+}
+
+// Load implements Source
+func (s *GlobSource) Load() ([]SLOWithFile, []ValidationError) {
+	files, err := filepath.Glob(s.Pattern)
+	if err != nil {
+		return nil, []ValidationError{{
+			File:    s.Pattern,
+			Message: fmt.Sprintf("invalid glob: %v", err),
+		}}
+	}
+
+	var slos []SLOWithFile
+	var errors []ValidationError
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			errors = append(errors, ValidationError{
+				File:    file,
+				Message: fmt.Sprintf("failed to read file: %v", err),
+			})
+			continue
+		}
+		parsed, parseErrors := parseYAMLDocuments(file, data)
+		slos = append(slos, parsed...)
+		errors = append(errors, parseErrors...)
+	}
+
+	return slos, errors
+}
+
+// MaxHTTPSourceBytes caps the response body of an HTTP source; larger responses fail
+// to load rather than exhaust memory
+const MaxHTTPSourceBytes = 16 << 20
+
+// HTTPSource loads a YAML document stream (documents separated by ---) from an
+// HTTP(S) URL. It sends the last ETag as If-None-Match and reuses the previous
+// result when the server answers 304 Not Modified.
+type HTTPSource struct {
+	URL    string
+	Client *http.Client
+
+	mu     sync.Mutex
+	etag   string
+	slos   []SLOWithFile
+	errors []ValidationError
+}
+
+// NewHTTPSource creates an HTTP source with a 10s request timeout
+func NewHTTPSource(url string) *HTTPSource {
+	return &HTTPSource{
+		URL:    url,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements Source
+func (s *HTTPSource) Name() string {
+	return s.URL
+}
+
+// Load implements Source
+func (s *HTTPSource) Load() ([]SLOWithFile, []ValidationError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fail := func(format string, args ...interface{}) ([]SLOWithFile, []ValidationError) {
+		return nil, []ValidationError{{File: s.URL, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.URL, nil)
+	if err != nil {
+		return fail("invalid URL: %v", err)
+	}
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fail("failed to fetch: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && s.etag != "" {
+		return cloneSLOFiles(s.slos), s.errors
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fail("failed to fetch: http status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxHTTPSourceBytes+1))
+	if err != nil {
+		return fail("failed to read response: %v", err)
+	}
+	if len(data) > MaxHTTPSourceBytes {
+		return fail("response exceeds %d bytes", MaxHTTPSourceBytes)
+	}
+
+	s.slos, s.errors = parseYAMLDocuments(s.URL, data)
+	s.etag = resp.Header.Get("ETag")
+
+	return cloneSLOFiles(s.slos), s.errors
+}
+
+// GitSource loads *.yaml and *.yml files under Path from a local git worktree at
+// Ref, without touching the checked-out files. The ref is resolved on every load,
+// so branch refs follow new commits while tags and commit hashes stay pinned.
+type GitSource struct {
+	Worktree string
+	Ref      string
+	Path     string
+}
+
+// Name implements Source
+func (s *GitSource) Name() string {
+	name := "git:" + s.Worktree + "@" + s.Ref
+	if s.Path != "" {
+		name += ":" + s.Path
+	}
+	return name
+}
+
+// Load implements Source
+func (s *GitSource) Load() ([]SLOWithFile, []ValidationError) {
+	commit, err := s.git("rev-parse", "--verify", "--end-of-options", s.Ref+"^{commit}")
+	if err != nil {
+		return nil, []ValidationError{{File: s.Name(), Message: fmt.Sprintf("failed to resolve ref: %v", err)}}
+	}
+	commit = strings.TrimSpace(commit)
+
+	args := []string{"ls-tree", "-r", "--name-only", commit}
+	if s.Path != "" {
+		args = append(args, "--", s.Path)
+	}
+	listing, err := s.git(args...)
+	if err != nil {
+		return nil, []ValidationError{{File: s.Name(), Message: fmt.Sprintf("failed to list files: %v", err)}}
+	}
+
+	var files []string
+	for _, file := range strings.Split(listing, "\n") {
+		if ext := path.Ext(file); ext == ".yaml" || ext == ".yml" {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+
+	var slos []SLOWithFile
+	var errors []ValidationError
+	for _, file := range files {
+		label := fmt.Sprintf("%s@%s:%s", s.Worktree, s.Ref, file)
+
+		data, err := s.git("show", commit+":"+file)
+		if err != nil {
+			errors = append(errors, ValidationError{File: label, Message: fmt.Sprintf("failed to read file: %v", err)})
+			continue
+		}
+		parsed, parseErrors := parseYAMLDocuments(label, []byte(data))
+		slos = append(slos, parsed...)
+		errors = append(errors, parseErrors...)
+	}
+
+	return slos, errors
+}
+
+// git runs a git command against the worktree and returns its stdout
+func (s *GitSource) git(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", s.Worktree}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+// parseYAMLDocuments parses a stream of one or more SLO documents. When the
+// stream holds several documents, each is labelled <file>[<index>].
+func parseYAMLDocuments(file string, data []byte) ([]SLOWithFile, []ValidationError) {
+	var docs []*SLO
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var slo SLO
+		err := decoder.Decode(&slo)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, []ValidationError{{File: file, Message: fmt.Sprintf("failed to parse YAML: %v", err)}}
+		}
+		docs = append(docs, &slo)
+	}
+
+	slos := make([]SLOWithFile, len(docs))
+	for i, doc := range docs {
+		label := file
+		if len(docs) > 1 {
+			label = fmt.Sprintf("%s[%d]", file, i)
+		}
+		slos[i] = SLOWithFile{SLO: doc, File: label}
+	}
+
+	return slos, nil
+}
+
+// cloneSLOFiles copies the slice so callers may tag entries without touching the cache
+func cloneSLOFiles(slos []SLOWithFile) []SLOWithFile {
+	return append([]SLOWithFile(nil), slos...)
+}
@@ -0,0 +1,237 @@
+package slo
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const sourceTestSLO = `apiVersion: aegis.dev/v1
+kind: SLO
+metadata:
+  id: %s
+  service: test
+spec:
+  environment: prod
+  objective: 0.999
+  complianceWindow: 30d
+  evaluationInterval: 30s
+  sli:
+    type: ratio
+    good:
+      prometheusQuery: good[{{window}}]
+    total:
+      prometheusQuery: total[{{window}}]
+  burnPolicy:
+    rules:
+      - name: fast-burn
+        shortWindow: 5m
+        longWindow: 1h
+        threshold: 14
+        action: BLOCK
+  gating:
+    minDataPoints: 1
+    stalenessLimit: 120s
+`
+
+func sourceTestDoc(id string) string {
+	return fmt.Sprintf(sourceTestSLO, id)
+}
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		spec string
+		want Source
+	}{
+		{"../../fixtures/slo/valid", &DirectorySource{Path: "../../fixtures/slo/valid"}},
+		{"dir:/etc/slos", &DirectorySource{Path: "/etc/slos"}},
+		{"../../fixtures/slo/valid/payment-availability.yaml", &FileSource{Path: "../../fixtures/slo/valid/payment-availability.yaml"}},
+		{"file:/etc/slos/[prod].yaml", &FileSource{Path: "/etc/slos/[prod].yaml"}},
+		{"glob:/etc/slos/*-prod.yaml", &GlobSource{Pattern: "/etc/slos/*-prod.yaml"}},
+		{"git:/srv/config@v1.4.0:slos", &GitSource{Worktree: "/srv/config", Ref: "v1.4.0", Path: "slos"}},
+		{"git:/srv/config@main", &GitSource{Worktree: "/srv/config", Ref: "main"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseSource(tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Name() != tt.want.Name() {
+				t.Errorf("expected %s, got %s", tt.want.Name(), got.Name())
+			}
+		})
+	}
+
+	if source, err := ParseSource("https://config.internal/slos.yaml"); err != nil || source.Name() != "https://config.internal/slos.yaml" {
+		t.Errorf("expected HTTP source, got %v (%v)", source, err)
+	}
+
+	for _, spec := range []string{"git:/srv/config", "git:/srv/config@", "does-not-exist"} {
+		if _, err := ParseSource(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestGlobSource(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a-prod.yaml"), sourceTestDoc("a-prod"))
+	writeFile(t, filepath.Join(dir, "b-prod.yaml"), sourceTestDoc("b-prod")+"---\n"+sourceTestDoc("c-prod"))
+	writeFile(t, filepath.Join(dir, "d-staging.yaml"), sourceTestDoc("d-staging"))
+
+	slos, errors := (&GlobSource{Pattern: filepath.Join(dir, "*-prod.yaml")}).Load()
+	if len(errors) != 0 {
+		t.Fatalf("unexpected errors: %v", errors)
+	}
+	if len(slos) != 3 {
+		t.Fatalf("expected 3 SLOs, got %d", len(slos))
+	}
+	if filepath.Base(slos[2].File) != "b-prod.yaml[1]" {
+		t.Errorf("expected multi-document label, got %s", slos[2].File)
+	}
+}
+
+func TestFileSource_GlobCharacters(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checkout[prod].yaml")
+	writeFile(t, path, sourceTestDoc("checkout-prod"))
+	writeFile(t, filepath.Join(dir, "checkoutp.yaml"), sourceTestDoc("checkout-p"))
+
+	source, err := ParseSource(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	slos, errors := source.Load()
+	if len(errors) != 0 {
+		t.Fatalf("unexpected errors: %v", errors)
+	}
+	if len(slos) != 1 || slos[0].SLO.Metadata.ID != "checkout-prod" {
+		t.Fatalf("expected only checkout-prod, got %d SLOs", len(slos))
+	}
+}
+
+func TestHTTPSource_SizeLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sourceTestDoc("remote-a")))
+		w.Write(bytes.Repeat([]byte("#"), MaxHTTPSourceBytes))
+	}))
+	defer server.Close()
+
+	slos, errors := NewHTTPSource(server.URL).Load()
+	if len(slos) != 0 || len(errors) != 1 || !strings.Contains(errors[0].Message, "exceeds") {
+		t.Errorf("expected a size error, got %d SLOs and %v", len(slos), errors)
+	}
+}
+
+func TestHTTPSource_ETag(t *testing.T) {
+	var fetches, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(sourceTestDoc("remote-a") + "---\n" + sourceTestDoc("remote-b")))
+	}))
+	defer server.Close()
+
+	source := NewHTTPSource(server.URL + "/slos.yaml")
+
+	for i := 0; i < 2; i++ {
+		slos, errors := source.Load()
+		if len(errors) != 0 {
+			t.Fatalf("load %d: unexpected errors: %v", i, errors)
+		}
+		if len(slos) != 2 || slos[1].SLO.Metadata.ID != "remote-b" {
+			t.Fatalf("load %d: expected remote-a and remote-b, got %d SLOs", i, len(slos))
+		}
+	}
+
+	if atomic.LoadInt32(&fetches) != 2 || atomic.LoadInt32(&notModified) != 1 {
+		t.Errorf("expected second load to be a 304, got %d fetches and %d not-modified", fetches, notModified)
+	}
+}
+
+func TestGitSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	git("init", "-q")
+	writeFile(t, filepath.Join(dir, "slos", "pinned.yaml"), sourceTestDoc("pinned"))
+	writeFile(t, filepath.Join(dir, "README.md"), "not an SLO")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+	git("tag", "v1")
+
+	// Changes after the pinned ref, committed or not, are ignored
+	writeFile(t, filepath.Join(dir, "slos", "later.yaml"), sourceTestDoc("later"))
+	git("add", "-A")
+	git("commit", "-q", "-m", "later")
+	writeFile(t, filepath.Join(dir, "slos", "pinned.yaml"), "not: [valid")
+
+	slos, errors := (&GitSource{Worktree: dir, Ref: "v1", Path: "slos"}).Load()
+	if len(errors) != 0 {
+		t.Fatalf("unexpected errors: %v", errors)
+	}
+	if len(slos) != 1 || slos[0].SLO.Metadata.ID != "pinned" {
+		t.Fatalf("expected only the pinned SLO, got %d", len(slos))
+	}
+
+	if _, errors := (&GitSource{Worktree: dir, Ref: "missing"}).Load(); len(errors) == 0 {
+		t.Error("expected error for unknown ref")
+	}
+}
+
+func TestValidateSLOs_CrossSourceCollision(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "local.yaml"), sourceTestDoc("shared-id"))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sourceTestDoc("shared-id")))
+	}))
+	defer server.Close()
+
+	slos, loadErrors := LoadSources([]Source{&DirectorySource{Path: dir}, NewHTTPSource(server.URL)})
+	if len(loadErrors) != 0 {
+		t.Fatalf("unexpected load errors: %v", loadErrors)
+	}
+
+	errors := mustNewValidator(t).ValidateSLOs(slos)
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 collision error, got %d: %v", len(errors), errors)
+	}
+	if !contains(errors[0].Message, "duplicate ID") || !contains(errors[0].Message, "from source dir:") {
+		t.Errorf("expected cross-source duplicate error, got %q", errors[0].Message)
+	}
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
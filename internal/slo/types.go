@@ -1,5 +1,7 @@
 package slo
 
+import "fmt"
+
 // SLO represents the parsed SLO definition
 type SLO struct {
 	APIVersion string   `yaml:"apiVersion"`
@@ -18,21 +20,96 @@ type Metadata struct {
 
 // Spec contains SLO specification
 type Spec struct {
-	Environment        string     `yaml:"environment"`
-	Objective          float64    `yaml:"objective"`
-	ComplianceWindow   string     `yaml:"complianceWindow"`
-	EvaluationInterval string     `yaml:"evaluationInterval"`
-	SLI                SLI        `yaml:"sli"`
-	BurnPolicy         BurnPolicy `yaml:"burnPolicy"`
-	Gating             Gating     `yaml:"gating"`
+	Environment        string          `yaml:"environment"`
+	Objective          float64         `yaml:"objective,omitempty"`
+	ComplianceWindow   string          `yaml:"complianceWindow,omitempty"`
+	CalendarWindow     *CalendarWindow `yaml:"calendarWindow,omitempty"`
+	EvaluationInterval string          `yaml:"evaluationInterval"`
+	SLI                SLI             `yaml:"sli,omitempty"`
+	Composite          *Composite      `yaml:"composite,omitempty"`
+	BurnPolicy         BurnPolicy      `yaml:"burnPolicy"`
+	Gating             Gating          `yaml:"gating"`
+	Matrix             Matrix          `yaml:"matrix,omitempty"`
+	ExpandBy           string          `yaml:"expandBy,omitempty"`
+	ExpandMatch        string          `yaml:"expandMatch,omitempty"`
+}
+
+// Matrix maps variable names to the values a spec is expanded over,
+// e.g. {env: [staging, prod], region: [us, eu]}; see ExpandMatrix
+type Matrix map[string][]string
+
+// Composite defines how a CompositeSLO aggregates its component SLOs
+type Composite struct {
+	Aggregation string               `yaml:"aggregation"` // weighted_ratio or min
+	Components  []CompositeComponent `yaml:"components"`
+}
+
+// CompositeComponent references a component SLO by ID
+type CompositeComponent struct {
+	SLOID  string  `yaml:"sloID"`
+	Weight float64 `yaml:"weight,omitempty"` // weighted_ratio only; defaults to 1
+}
+
+// EffectiveWeight returns the component weight, defaulting to 1
+func (c CompositeComponent) EffectiveWeight() float64 {
+	if c.Weight <= 0 {
+		return 1
+	}
+	return c.Weight
+}
+
+// SLO kinds
+const (
+	KindSLO          = "SLO"
+	KindCompositeSLO = "CompositeSLO"
+)
+
+// IsComposite reports whether the SLO aggregates other SLOs
+func (s *SLO) IsComposite() bool {
+	return s.Kind == KindCompositeSLO
 }
 
 // SLI defines the Service Level Indicator
 type SLI struct {
-	Type        string   `yaml:"type"`
-	ThresholdMs *int     `yaml:"thresholdMs,omitempty"`
+	Type        string     `yaml:"type"`
+	ThresholdMs *int       `yaml:"thresholdMs,omitempty"`
+	Good        QueryRef   `yaml:"good,omitempty"`
+	Total       QueryRef   `yaml:"total,omitempty"`
+	TimeSlice   *TimeSlice `yaml:"timeSlice,omitempty"`
+	Targets     []Target   `yaml:"targets,omitempty"`
+	// Labels to rank the contributors to bad events by when a window burns; the good
+	// and total queries must keep them
+	BreakdownBy []string `yaml:"breakdownBy,omitempty"`
+	// The good and total queries return event counts over the window (e.g. increase()),
+	// so the budget can also be counted in bad events
+	CountsEvents bool `yaml:"countsEvents,omitempty"`
+}
+
+// Target is one threshold/objective pair of a latency_distribution SLI,
+// e.g. "90% of requests under 200ms". Each target is evaluated against the
+// shared total query with its own burn rates and budget.
+type Target struct {
+	Name        string   `yaml:"name,omitempty"`
+	ThresholdMs int      `yaml:"thresholdMs"`
+	Objective   float64  `yaml:"objective"`
 	Good        QueryRef `yaml:"good"`
-	Total       QueryRef `yaml:"total"`
+}
+
+// TargetName returns the target's name, defaulting to its threshold (e.g. "200ms")
+func (t Target) TargetName() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return fmt.Sprintf("%dms", t.ThresholdMs)
+}
+
+// TimeSlice defines a time_slice SLI. Each slice is good when the query value
+// meets the target; the SLI is the fraction of good slices in a window.
+type TimeSlice struct {
+	Query      QueryRef `yaml:"query"`
+	Slice      string   `yaml:"slice"`
+	Target     float64  `yaml:"target"`
+	Comparison string   `yaml:"comparison,omitempty"` // >=, >, <=, < (default >=)
 }
 
 // QueryRef contains the Prometheus query
@@ -40,9 +117,28 @@ type QueryRef struct {
 	PrometheusQuery string `yaml:"prometheusQuery"`
 }
 
+// ForTarget returns a copy of a latency_distribution SLO reduced to a single
+// latency_threshold target with the target's objective and good query, keeping the
+// distribution's breakdown labels
+func (s *SLO) ForTarget(t Target) *SLO {
+	targetSLO := *s
+	thresholdMs := t.ThresholdMs
+	targetSLO.Spec.Objective = t.Objective
+	targetSLO.Spec.SLI = SLI{
+		Type:         "latency_threshold",
+		ThresholdMs:  &thresholdMs,
+		Good:         t.Good,
+		Total:        s.Spec.SLI.Total,
+		BreakdownBy:  s.Spec.SLI.BreakdownBy,
+		CountsEvents: s.Spec.SLI.CountsEvents,
+	}
+	return &targetSLO
+}
+
 // BurnPolicy defines burn rate policies
 type BurnPolicy struct {
-	Rules []BurnRule `yaml:"rules"`
+	Rules       []BurnRule   `yaml:"rules"`
+	BudgetRules []BudgetRule `yaml:"budgetRules,omitempty"`
 }
 
 // BurnRule defines a single burn rate rule
@@ -52,18 +148,64 @@ type BurnRule struct {
 	LongWindow  string  `yaml:"longWindow"`
 	Threshold   float64 `yaml:"threshold"`
 	Action      string  `yaml:"action"`
+
+	// Hysteresis: the rule fires once triggered for For, then holds until both burn
+	// rates stay below ClearBelow (default Threshold) for Cooldown
+	For        string  `yaml:"for,omitempty"`
+	ClearBelow float64 `yaml:"clearBelow,omitempty"`
+	Cooldown   string  `yaml:"cooldown,omitempty"`
+}
+
+// HasHysteresis reports whether the rule's decision depends on its past evaluations
+func (r BurnRule) HasHysteresis() bool {
+	return r.For != "" || r.ClearBelow > 0 || r.Cooldown != ""
+}
+
+// ClearLevel returns the burn rate a fired rule must fall below to clear
+func (r BurnRule) ClearLevel() float64 {
+	if r.ClearBelow > 0 {
+		return r.ClearBelow
+	}
+	return r.Threshold
+}
+
+// BudgetRule triggers its action when less than Threshold (a fraction) of the error
+// budget remains, regardless of the current burn rate
+type BudgetRule struct {
+	Name      string  `yaml:"name"`
+	Threshold float64 `yaml:"threshold"`
+	Action    string  `yaml:"action"`
 }
 
 // Gating defines gating configuration
 type Gating struct {
 	MinDataPoints  int    `yaml:"minDataPoints"`
 	StalenessLimit string `yaml:"stalenessLimit"`
+	// Windows with fewer total events are insufficient data and cannot trigger rules
+	MinEvents float64 `yaml:"minEvents,omitempty"`
+	// Two-sided confidence level (e.g. 0.95) of the Wilson interval on each window's
+	// error rate; when set, rules compare the burn rate's lower bound to the threshold
+	Confidence float64 `yaml:"confidence,omitempty"`
+	// Action when a window has no data timestamp: ALLOW (the default, treat it as
+	// fresh), WARN or BLOCK
+	OnUnknownFreshness string `yaml:"onUnknownFreshness,omitempty"`
+	// Action when data is stale: ALLOW, WARN (the default) or BLOCK
+	OnStale string `yaml:"onStale,omitempty"`
+	// Action on insufficient data (zero traffic, too few samples or events): ALLOW,
+	// WARN (the default) or BLOCK
+	OnNoTraffic string `yaml:"onNoTraffic,omitempty"`
+	// Action when the evaluation or some of its queries fail: ALLOW, WARN or BLOCK;
+	// defaults to the server's --on-query-error
+	OnQueryError string `yaml:"onQueryError,omitempty"`
 }
 
 // SLOWithFile pairs an SLO with its source file path
 type SLOWithFile struct {
-	SLO  *SLO
-	File string
+	SLO    *SLO
+	File   string
+	Vars   map[string]string // matrix or expandBy variables this SLO was expanded with, if any
+	Parent string            // ID of the dynamic SLO this child was generated from, if any
+	Source string            // name of the source the SLO was loaded from, if any
 }
 
 // ValidationError represents a validation error for a specific file
@@ -31,23 +31,35 @@ func NewValidator(schemaPath string) (*Validator, error) {
 
 // ValidateDirectory loads and validates all SLO files in a directory
 func (v *Validator) ValidateDirectory(dirPath string) []ValidationError {
-	sloWithFiles, loadErrors := LoadFromDirectory(dirPath)
+	sloWithFiles, loadErrors := loadRawFromDirectory(dirPath)
 
 	var allErrors []ValidationError
 	allErrors = append(allErrors, loadErrors...)
+	allErrors = append(allErrors, v.ValidateSLOs(sloWithFiles)...)
+
+	return allErrors
+}
+
+// ValidateSLOs validates SLOs as loaded from one or more sources, before matrix expansion
+func (v *Validator) ValidateSLOs(sloWithFiles []SLOWithFile) []ValidationError {
+	var allErrors []ValidationError
 
 	if len(sloWithFiles) == 0 {
 		return allErrors
 	}
 
-	// Validate each SLO against JSON schema
+	// Validate each SLO file against JSON schema
 	for _, sloWithFile := range sloWithFiles {
 		schemaErrors := v.validateSchema(sloWithFile.File, sloWithFile.SLO)
 		allErrors = append(allErrors, schemaErrors...)
 	}
 
+	// Expand matrix specs; extra rules apply to the concrete SLOs
+	expanded, expandErrors := ExpandMatrix(sloWithFiles)
+	allErrors = append(allErrors, expandErrors...)
+
 	// Apply extra validation rules
-	extraErrors := v.validateExtraRules(sloWithFiles)
+	extraErrors := v.validateExtraRules(expanded)
 	allErrors = append(allErrors, extraErrors...)
 
 	return allErrors
@@ -119,23 +131,108 @@ func extractSchemaErrors(file string, err *jsonschema.ValidationError) []Validat
 func (v *Validator) validateExtraRules(sloWithFiles []SLOWithFile) []ValidationError {
 	var errors []ValidationError
 
-	// Check for duplicate IDs
-	idSeen := make(map[string]string)
+	// Check for duplicate IDs, including across sources
+	idSeen := make(map[string]SLOWithFile)
 	for _, sloWithFile := range sloWithFiles {
 		id := sloWithFile.SLO.Metadata.ID
-		if prevFile, exists := idSeen[id]; exists {
+		if prev, exists := idSeen[id]; exists {
+			message := fmt.Sprintf("duplicate ID %q (also in %s)", id, filepath.Base(prev.File))
+			if prev.Source != sloWithFile.Source {
+				message = fmt.Sprintf("duplicate ID %q (also in %s from source %s)", id, filepath.Base(prev.File), prev.Source)
+			}
 			errors = append(errors, ValidationError{
 				File:    sloWithFile.File,
 				Path:    "metadata.id",
-				Message: fmt.Sprintf("duplicate ID %q (also in %s)", id, filepath.Base(prevFile)),
+				Message: message,
 			})
 		} else {
-			idSeen[id] = sloWithFile.File
+			idSeen[id] = sloWithFile
 		}
 
 		// Check compliance window >= max burn policy window
 		complianceErrors := validateComplianceWindow(sloWithFile.File, sloWithFile.SLO)
 		errors = append(errors, complianceErrors...)
+
+		// Check time slice fits inside every evaluated window
+		timeSliceErrors := validateTimeSlice(sloWithFile.File, sloWithFile.SLO)
+		errors = append(errors, timeSliceErrors...)
+
+		// Check latency distribution targets are distinguishable
+		targetErrors := validateTargets(sloWithFile.File, sloWithFile.SLO)
+		errors = append(errors, targetErrors...)
+
+		// Check burn rule hysteresis
+		hysteresisErrors := validateHysteresis(sloWithFile.File, sloWithFile.SLO)
+		errors = append(errors, hysteresisErrors...)
+	}
+
+	// Check composite components reference loaded SLOs
+	compositeErrors := validateComposites(sloWithFiles)
+	errors = append(errors, compositeErrors...)
+
+	return errors
+}
+
+// validateComposites checks that every CompositeSLO component references a loaded,
+// non-composite SLO with a single SLI, and that no component is listed twice
+func validateComposites(sloWithFiles []SLOWithFile) []ValidationError {
+	var errors []ValidationError
+
+	byID := make(map[string]*SLO, len(sloWithFiles))
+	for _, sloWithFile := range sloWithFiles {
+		byID[sloWithFile.SLO.Metadata.ID] = sloWithFile.SLO
+	}
+
+	for _, sloWithFile := range sloWithFiles {
+		composite := sloWithFile.SLO.Spec.Composite
+		if !sloWithFile.SLO.IsComposite() || composite == nil {
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for i, component := range composite.Components {
+			path := fmt.Sprintf("spec.composite.components[%d].sloID", i)
+
+			if seen[component.SLOID] {
+				errors = append(errors, ValidationError{
+					File:    sloWithFile.File,
+					Path:    path,
+					Message: fmt.Sprintf("duplicate component %q", component.SLOID),
+				})
+				continue
+			}
+			seen[component.SLOID] = true
+
+			child, exists := byID[component.SLOID]
+			if !exists {
+				errors = append(errors, ValidationError{
+					File:    sloWithFile.File,
+					Path:    path,
+					Message: fmt.Sprintf("unknown SLO %q", component.SLOID),
+				})
+				continue
+			}
+
+			if child.IsComposite() {
+				errors = append(errors, ValidationError{
+					File:    sloWithFile.File,
+					Path:    path,
+					Message: fmt.Sprintf("component %q is a CompositeSLO; nesting is not supported", component.SLOID),
+				})
+			} else if child.Spec.SLI.Type == "latency_distribution" {
+				errors = append(errors, ValidationError{
+					File:    sloWithFile.File,
+					Path:    path,
+					Message: fmt.Sprintf("component %q is a latency_distribution SLO; reference a single-target SLO instead", component.SLOID),
+				})
+			} else if child.IsDynamic() {
+				errors = append(errors, ValidationError{
+					File:    sloWithFile.File,
+					Path:    path,
+					Message: fmt.Sprintf("component %q uses expandBy; its children are discovered at runtime and cannot be referenced", component.SLOID),
+				})
+			}
+		}
 	}
 
 	return errors
@@ -145,12 +242,12 @@ func (v *Validator) validateExtraRules(sloWithFiles []SLOWithFile) []ValidationE
 func validateComplianceWindow(file string, slo *SLO) []ValidationError {
 	var errors []ValidationError
 
-	complianceDur, err := ParseDuration(slo.Spec.ComplianceWindow)
+	complianceDur, path, err := complianceDuration(slo)
 	if err != nil {
 		errors = append(errors, ValidationError{
 			File:    file,
-			Path:    "spec.complianceWindow",
-			Message: fmt.Sprintf("invalid duration: %v", err),
+			Path:    path,
+			Message: err.Error(),
 		})
 		return errors
 	}
@@ -188,25 +285,123 @@ func validateComplianceWindow(file string, slo *SLO) []ValidationError {
 	if complianceDur < maxPolicyWindow {
 		errors = append(errors, ValidationError{
 			File: file,
-			Path: "spec.complianceWindow",
+			Path: path,
 			Message: fmt.Sprintf("complianceWindow (%s) must be >= max burn policy window (%s)",
-				slo.Spec.ComplianceWindow, formatDuration(maxPolicyWindow)),
+				slo.Spec.ComplianceWindowName(), FormatDuration(maxPolicyWindow)),
 		})
 	}
 
 	return errors
 }
 
-// formatDuration converts a time.Duration back to a duration string
-func formatDuration(d time.Duration) string {
-	if d%(24*time.Hour) == 0 {
-		return fmt.Sprintf("%dd", d/(24*time.Hour))
+// complianceDuration returns the (shortest) compliance window length and its spec path
+func complianceDuration(slo *SLO) (time.Duration, string, error) {
+	if cw := slo.Spec.CalendarWindow; cw != nil {
+		if _, err := cw.Location(); err != nil {
+			return 0, "spec.calendarWindow.timezone", err
+		}
+		d, err := cw.MinLength()
+		return d, "spec.calendarWindow.calendar", err
 	}
-	if d%time.Hour == 0 {
-		return fmt.Sprintf("%dh", d/time.Hour)
+
+	d, err := ParseDuration(slo.Spec.ComplianceWindow)
+	if err != nil {
+		return 0, "spec.complianceWindow", fmt.Errorf("invalid duration: %v", err)
 	}
-	if d%time.Minute == 0 {
-		return fmt.Sprintf("%dm", d/time.Minute)
+	return d, "spec.complianceWindow", nil
+}
+
+// validateTimeSlice checks that a time_slice SLI has a slice no longer than any window
+func validateTimeSlice(file string, slo *SLO) []ValidationError {
+	var errors []ValidationError
+
+	ts := slo.Spec.SLI.TimeSlice
+	if slo.Spec.SLI.Type != "time_slice" || ts == nil {
+		return errors
 	}
-	return fmt.Sprintf("%ds", d/time.Second)
+
+	sliceDur, err := ParseDuration(ts.Slice)
+	if err != nil {
+		errors = append(errors, ValidationError{
+			File:    file,
+			Path:    "spec.sli.timeSlice.slice",
+			Message: fmt.Sprintf("invalid duration: %v", err),
+		})
+		return errors
+	}
+
+	windows := []string{slo.Spec.ComplianceWindow}
+	for _, rule := range slo.Spec.BurnPolicy.Rules {
+		windows = append(windows, rule.ShortWindow, rule.LongWindow)
+	}
+
+	for _, window := range windows {
+		windowDur, err := ParseDuration(window)
+		if err != nil {
+			// Reported by validateComplianceWindow
+			continue
+		}
+		if sliceDur > windowDur {
+			errors = append(errors, ValidationError{
+				File: file,
+				Path: "spec.sli.timeSlice.slice",
+				Message: fmt.Sprintf("slice (%s) must be <= every evaluated window (%s)",
+					ts.Slice, window),
+			})
+			break
+		}
+	}
+
+	return errors
+}
+
+// validateHysteresis checks that burn rules clear below their threshold and that their
+// for and cooldown durations parse
+func validateHysteresis(file string, slo *SLO) []ValidationError {
+	var errors []ValidationError
+
+	for i, rule := range slo.Spec.BurnPolicy.Rules {
+		if rule.ClearBelow > rule.Threshold {
+			errors = append(errors, ValidationError{
+				File:    file,
+				Path:    fmt.Sprintf("spec.burnPolicy.rules[%d].clearBelow", i),
+				Message: fmt.Sprintf("clearBelow (%g) must be <= threshold (%g)", rule.ClearBelow, rule.Threshold),
+			})
+		}
+
+		for _, field := range []struct{ name, value string }{{"for", rule.For}, {"cooldown", rule.Cooldown}} {
+			if field.value == "" {
+				continue
+			}
+			if _, err := ParseDuration(field.value); err != nil {
+				errors = append(errors, ValidationError{
+					File:    file,
+					Path:    fmt.Sprintf("spec.burnPolicy.rules[%d].%s", i, field.name),
+					Message: fmt.Sprintf("invalid duration: %v", err),
+				})
+			}
+		}
+	}
+
+	return errors
+}
+
+// validateTargets checks that latency_distribution target names are unique
+func validateTargets(file string, slo *SLO) []ValidationError {
+	var errors []ValidationError
+
+	seen := make(map[string]bool)
+	for i, target := range slo.Spec.SLI.Targets {
+		name := target.TargetName()
+		if seen[name] {
+			errors = append(errors, ValidationError{
+				File:    file,
+				Path:    fmt.Sprintf("spec.sli.targets[%d]", i),
+				Message: fmt.Sprintf("duplicate target %q", name),
+			})
+		}
+		seen[name] = true
+	}
+
+	return errors
 }
@@ -95,6 +95,59 @@ func TestValidator_ValidateDirectory_InvalidFiles(t *testing.T) {
 	if !hasDuplicateError {
 		t.Error("expected error about duplicate IDs")
 	}
+
+	// Test composite-unknown-component.yaml
+	if errs, ok := errorsByFile["composite-unknown-component.yaml"]; ok {
+		hasUnknownError := false
+		for _, err := range errs {
+			if contains(err.Message, "unknown SLO") && contains(err.Message, "does-not-exist") {
+				hasUnknownError = true
+				break
+			}
+		}
+		if !hasUnknownError {
+			t.Errorf("expected error about unknown component SLO, got: %v", errs)
+		}
+	} else {
+		t.Error("expected errors for composite-unknown-component.yaml")
+	}
+
+	// Test matrix-unresolved.yaml
+	if errs, ok := errorsByFile["matrix-unresolved.yaml"]; ok {
+		hasPlaceholderError := false
+		for _, err := range errs {
+			if contains(err.Message, "unresolved placeholder {{region}}") {
+				hasPlaceholderError = true
+				break
+			}
+		}
+		if !hasPlaceholderError {
+			t.Errorf("expected error about unresolved placeholder, got: %v", errs)
+		}
+	} else {
+		t.Error("expected errors for matrix-unresolved.yaml")
+	}
+
+	// Test gating-invalid-action.yaml
+	if errs, ok := errorsByFile["gating-invalid-action.yaml"]; ok {
+		hasActionError := false
+		for _, err := range errs {
+			if contains(err.Path, "onNoTraffic") {
+				hasActionError = true
+				break
+			}
+		}
+		if !hasActionError {
+			t.Errorf("expected error about the onNoTraffic action, got: %v", errs)
+		}
+	} else {
+		t.Error("expected errors for gating-invalid-action.yaml")
+	}
+
+	// Test counts-events-invalid.yaml: time slices always count slices
+	if errs, ok := errorsByFile["counts-events-invalid.yaml"]; !ok || len(errs) == 0 {
+		t.Error("expected errors for counts-events-invalid.yaml")
+	}
 }
 
 func TestValidator_ValidateDirectory_MixedFiles(t *testing.T) {
@@ -272,3 +325,48 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestValidateTargets(t *testing.T) {
+	slo := &SLO{
+		Spec: Spec{
+			SLI: SLI{
+				Type: "latency_distribution",
+				Targets: []Target{
+					{ThresholdMs: 200, Objective: 0.9},
+					{ThresholdMs: 200, Objective: 0.99},
+					{Name: "p99", ThresholdMs: 1000, Objective: 0.99},
+				},
+			},
+		},
+	}
+
+	errors := validateTargets("test.yaml", slo)
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 duplicate target error, got %d: %v", len(errors), errors)
+	}
+	if errors[0].Path != "spec.sli.targets[1]" {
+		t.Errorf("expected error on targets[1], got %s", errors[0].Path)
+	}
+}
+
+func TestValidateHysteresis(t *testing.T) {
+	slo := &SLO{
+		Spec: Spec{
+			BurnPolicy: BurnPolicy{
+				Rules: []BurnRule{
+					{Name: "ok", Threshold: 14, ClearBelow: 7, For: "5m", Cooldown: "15m"},
+					{Name: "clears-above", Threshold: 6, ClearBelow: 7},
+					{Name: "bad-cooldown", Threshold: 6, Cooldown: "soon"},
+				},
+			},
+		},
+	}
+
+	errors := validateHysteresis("test.yaml", slo)
+	if len(errors) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(errors), errors)
+	}
+	if errors[0].Path != "spec.burnPolicy.rules[1].clearBelow" || errors[1].Path != "spec.burnPolicy.rules[2].cooldown" {
+		t.Errorf("unexpected error paths: %v", errors)
+	}
+}
@@ -62,3 +62,52 @@ CREATE TABLE IF NOT EXISTS latest_state (
 
 CREATE INDEX IF NOT EXISTS idx_latest_state_service_env ON latest_state(service, environment);
 `
+
+// Migrations alter the schema of existing databases. Migration i brings the database
+// to user_version i+1; append new migrations, never edit applied ones.
+var Migrations = []string{
+	// 1: mark evaluations written by a backfill
+	`ALTER TABLE evaluations ADD COLUMN backfilled BOOLEAN NOT NULL DEFAULT 0`,
+	// 2: record evaluations whose queries failed
+	`ALTER TABLE evaluations ADD COLUMN query_failed BOOLEAN NOT NULL DEFAULT 0;
+	 ALTER TABLE latest_state ADD COLUMN query_failed BOOLEAN NOT NULL DEFAULT 0`,
+	// 3: record the budget forecast of each evaluation
+	`ALTER TABLE evaluations ADD COLUMN forecast_json TEXT`,
+	// 4: record the error budget in bad events
+	`ALTER TABLE evaluations ADD COLUMN budget_events_json TEXT`,
+	// 5: decision overrides and the record of their creation, use and expiry
+	`CREATE TABLE overrides (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		slo_id TEXT NOT NULL,
+		decision TEXT NOT NULL,
+		approver TEXT NOT NULL,
+		reason TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		expires_at TIMESTAMP NOT NULL,
+		expiry_recorded BOOLEAN NOT NULL DEFAULT 0
+	);
+	CREATE INDEX idx_overrides_slo_id ON overrides(slo_id, expires_at);
+	CREATE TABLE override_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		override_id INTEGER NOT NULL,
+		slo_id TEXT NOT NULL,
+		event TEXT NOT NULL,
+		decision TEXT NOT NULL,
+		original_decision TEXT,
+		timestamp TIMESTAMP NOT NULL,
+		FOREIGN KEY (override_id) REFERENCES overrides(id)
+	);
+	CREATE INDEX idx_override_events_slo_id ON override_events(slo_id, timestamp DESC)`,
+	// 6: hysteresis state of burn rules
+	`CREATE TABLE rule_states (
+		slo_id TEXT NOT NULL,
+		target TEXT NOT NULL,
+		rule TEXT NOT NULL,
+		pending_since TIMESTAMP,
+		firing_since TIMESTAMP,
+		clearing_since TIMESTAMP,
+		PRIMARY KEY (slo_id, target, rule)
+	)`,
+	// 7: the decision an override currently replaces, empty while it does not apply
+	`ALTER TABLE overrides ADD COLUMN replacing TEXT NOT NULL DEFAULT ''`,
+}
@@ -0,0 +1,300 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/storage"
+)
+
+// overrideColumns are the columns scanned by scanOverride. Override times are stored in
+// UTC so that SQLite compares them in order.
+const overrideColumns = `id, slo_id, decision, approver, reason, created_at, expires_at`
+
+// CreateOverride persists an override, setting its ID, and records its creation
+func (s *Store) CreateOverride(override *storage.Override) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO overrides (slo_id, decision, approver, reason, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, override.SLOID, override.Decision, override.Approver, override.Reason,
+		override.CreatedAt.UTC(), override.ExpiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to store override: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get override ID: %w", err)
+	}
+
+	if err := insertOverrideEvent(tx, storage.OverrideEvent{
+		OverrideID: id,
+		SLOID:      override.SLOID,
+		Event:      storage.OverrideCreated,
+		Decision:   override.Decision,
+		Timestamp:  override.CreatedAt,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit override: %w", err)
+	}
+
+	override.ID = id
+	return nil
+}
+
+// UseOverride returns the most recently created override of an SLO active at the given
+// time; nil when there is none. A use is recorded each time the override starts
+// changing originalDecision, or starts replacing a different decision, so repeated
+// polls of the same decision add no events.
+func (s *Store) UseOverride(sloID string, originalDecision string, at time.Time) (*storage.Override, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRow(`
+		SELECT `+overrideColumns+`
+		FROM overrides
+		WHERE slo_id = ? AND created_at <= ? AND expires_at > ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`, sloID, at.UTC(), at.UTC())
+
+	override, err := scanOverride(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active override: %w", err)
+	}
+
+	var replacing string
+	if err := tx.QueryRow(`SELECT replacing FROM overrides WHERE id = ?`, override.ID).Scan(&replacing); err != nil {
+		return nil, fmt.Errorf("failed to get replaced decision: %w", err)
+	}
+
+	// An override that does not change the decision stops replacing it.
+	if override.Decision == originalDecision {
+		originalDecision = ""
+	}
+	if replacing == originalDecision {
+		return override, nil
+	}
+
+	if _, err := tx.Exec(`UPDATE overrides SET replacing = ? WHERE id = ?`, originalDecision, override.ID); err != nil {
+		return nil, fmt.Errorf("failed to update override: %w", err)
+	}
+
+	if originalDecision == "" {
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to commit override use: %w", err)
+		}
+		return override, nil
+	}
+
+	if err := insertOverrideEvent(tx, storage.OverrideEvent{
+		OverrideID:       override.ID,
+		SLOID:            override.SLOID,
+		Event:            storage.OverrideUsed,
+		Decision:         override.Decision,
+		OriginalDecision: originalDecision,
+		Timestamp:        at,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit override use: %w", err)
+	}
+
+	return override, nil
+}
+
+// ListOverrides returns the overrides active at the given time, optionally of one SLO
+func (s *Store) ListOverrides(sloID string, at time.Time) ([]storage.Override, error) {
+	query := `
+		SELECT ` + overrideColumns + `
+		FROM overrides
+		WHERE created_at <= ? AND expires_at > ?
+	`
+	args := []interface{}{at.UTC(), at.UTC()}
+
+	if sloID != "" {
+		query += " AND slo_id = ?"
+		args = append(args, sloID)
+	}
+
+	query += " ORDER BY created_at DESC, id DESC"
+
+	return queryOverrides(s.db, query, args...)
+}
+
+// ExpireOverrides records the expiry of the overrides that expired by the given time and
+// returns them; each expiry is recorded once, timestamped with the override's expiry
+func (s *Store) ExpireOverrides(at time.Time) ([]storage.Override, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	expired, err := queryOverrides(tx, `
+		SELECT `+overrideColumns+`
+		FROM overrides
+		WHERE expiry_recorded = 0 AND expires_at <= ?
+		ORDER BY expires_at, id
+	`, at.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, override := range expired {
+		if err := insertOverrideEvent(tx, storage.OverrideEvent{
+			OverrideID: override.ID,
+			SLOID:      override.SLOID,
+			Event:      storage.OverrideExpired,
+			Decision:   override.Decision,
+			Timestamp:  override.ExpiresAt,
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec("UPDATE overrides SET expiry_recorded = 1 WHERE id = ?", override.ID); err != nil {
+			return nil, fmt.Errorf("failed to mark override %d expired: %w", override.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit override expiry: %w", err)
+	}
+
+	return expired, nil
+}
+
+// QueryOverrideEvents retrieves override events, most recent first
+func (s *Store) QueryOverrideEvents(filter storage.OverrideEventFilter) ([]storage.OverrideEvent, error) {
+	query := `
+		SELECT id, override_id, slo_id, event, decision, original_decision, timestamp
+		FROM override_events
+		WHERE 1=1
+	`
+	args := []interface{}{}
+
+	if filter.SLOID != "" {
+		query += " AND slo_id = ?"
+		args = append(args, filter.SLOID)
+	}
+
+	query += " ORDER BY timestamp DESC, id DESC"
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	} else {
+		query += " LIMIT 100" // Default limit
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query override events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []storage.OverrideEvent
+	for rows.Next() {
+		var event storage.OverrideEvent
+		var originalDecision sql.NullString
+		if err := rows.Scan(
+			&event.ID,
+			&event.OverrideID,
+			&event.SLOID,
+			&event.Event,
+			&event.Decision,
+			&originalDecision,
+			&event.Timestamp,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		event.OriginalDecision = originalDecision.String
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return events, nil
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// queryOverrides runs a query selecting overrideColumns
+func queryOverrides(q queryer, query string, args ...interface{}) ([]storage.Override, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query overrides: %w", err)
+	}
+	defer rows.Close()
+
+	var overrides []storage.Override
+	for rows.Next() {
+		override, err := scanOverride(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		overrides = append(overrides, *override)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// scanOverride scans a row of overrideColumns
+func scanOverride(row interface{ Scan(...interface{}) error }) (*storage.Override, error) {
+	var override storage.Override
+	if err := row.Scan(
+		&override.ID,
+		&override.SLOID,
+		&override.Decision,
+		&override.Approver,
+		&override.Reason,
+		&override.CreatedAt,
+		&override.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+// insertOverrideEvent records an override event
+func insertOverrideEvent(tx *sql.Tx, event storage.OverrideEvent) error {
+	var originalDecision sql.NullString
+	if event.OriginalDecision != "" {
+		originalDecision = sql.NullString{String: event.OriginalDecision, Valid: true}
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO override_events (override_id, slo_id, event, decision, original_decision, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, event.OverrideID, event.SLOID, event.Event, event.Decision, originalDecision, event.Timestamp.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to record override %s: %w", event.Event, err)
+	}
+	return nil
+}
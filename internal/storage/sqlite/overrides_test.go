@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/storage"
+)
+
+func TestStore_Overrides(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	create := func(sloID string, created time.Time, ttl time.Duration) *storage.Override {
+		override := &storage.Override{
+			SLOID:     sloID,
+			Decision:  "ALLOW",
+			Approver:  "alice",
+			Reason:    "hotfix for INC-42",
+			CreatedAt: created,
+			ExpiresAt: created.Add(ttl),
+		}
+		if err := store.CreateOverride(override); err != nil {
+			t.Fatalf("failed to create override: %v", err)
+		}
+		return override
+	}
+
+	first := create("checkout", now.Add(-2*time.Hour), time.Hour) // expired an hour ago
+	second := create("checkout", now.Add(-30*time.Minute), time.Hour)
+	create("payment", now.Add(-10*time.Minute), 2*time.Hour)
+
+	active, err := store.ListOverrides("", now)
+	if err != nil {
+		t.Fatalf("failed to list overrides: %v", err)
+	}
+	if len(active) != 2 {
+		t.Errorf("expected 2 active overrides, got %+v", active)
+	}
+
+	used, err := store.UseOverride("checkout", "BLOCK", now)
+	if err != nil {
+		t.Fatalf("failed to use override: %v", err)
+	}
+	if used == nil || used.ID != second.ID || !used.ExpiresAt.Equal(second.ExpiresAt) {
+		t.Fatalf("expected override %d, got %+v", second.ID, used)
+	}
+
+	// A repeated poll records no further use; once a decision the override leaves
+	// unchanged comes in between, the next change is recorded again
+	for i, originalDecision := range []string{"BLOCK", "ALLOW", "BLOCK", "BLOCK"} {
+		at := now.Add(time.Duration(i+1) * time.Minute)
+		if again, err := store.UseOverride("checkout", originalDecision, at); err != nil || again == nil || again.ID != second.ID {
+			t.Errorf("expected override %d for %s, got %+v (err %v)", second.ID, originalDecision, again, err)
+		}
+	}
+
+	if none, err := store.UseOverride("search", "BLOCK", now); err != nil || none != nil {
+		t.Errorf("expected no override for search, got %+v (err %v)", none, err)
+	}
+
+	// The expiry of the first override is recorded once
+	for i, expectedCount := range []int{1, 0} {
+		expired, err := store.ExpireOverrides(now)
+		if err != nil {
+			t.Fatalf("failed to expire overrides: %v", err)
+		}
+		if len(expired) != expectedCount || (expectedCount > 0 && expired[0].ID != first.ID) {
+			t.Errorf("sweep %d: expected %d expired overrides, got %+v", i, expectedCount, expired)
+		}
+	}
+
+	events, err := store.QueryOverrideEvents(storage.OverrideEventFilter{SLOID: "checkout"})
+	if err != nil {
+		t.Fatalf("failed to query override events: %v", err)
+	}
+
+	expected := []struct {
+		event            string
+		overrideID       int64
+		originalDecision string
+	}{
+		{event: storage.OverrideUsed, overrideID: second.ID, originalDecision: "BLOCK"},
+		{event: storage.OverrideUsed, overrideID: second.ID, originalDecision: "BLOCK"},
+		{event: storage.OverrideCreated, overrideID: second.ID},
+		{event: storage.OverrideExpired, overrideID: first.ID},
+		{event: storage.OverrideCreated, overrideID: first.ID},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %+v", len(expected), events)
+	}
+	if !events[0].Timestamp.Equal(now.Add(3 * time.Minute)) {
+		t.Errorf("expected the second use at %v, got %v", now.Add(3*time.Minute), events[0].Timestamp)
+	}
+	for i, event := range events {
+		if event.Event != expected[i].event || event.OverrideID != expected[i].overrideID || event.OriginalDecision != expected[i].originalDecision {
+			t.Errorf("event %d: expected %+v, got %+v", i, expected[i], event)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/policy"
+)
+
+// LoadRuleStates returns the rule states of all SLOs
+func (s *Store) LoadRuleStates() ([]policy.RuleState, error) {
+	rows, err := s.db.Query(`
+		SELECT slo_id, target, rule, pending_since, firing_since, clearing_since
+		FROM rule_states
+		ORDER BY slo_id, target, rule
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rule states: %w", err)
+	}
+	defer rows.Close()
+
+	var states []policy.RuleState
+	for rows.Next() {
+		var state policy.RuleState
+		var pendingSince, firingSince, clearingSince sql.NullTime
+		if err := rows.Scan(
+			&state.SLOID,
+			&state.Target,
+			&state.Rule,
+			&pendingSince,
+			&firingSince,
+			&clearingSince,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		state.PendingSince = pendingSince.Time
+		state.FiringSince = firingSince.Time
+		state.ClearingSince = clearingSince.Time
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return states, nil
+}
+
+// SaveRuleStates replaces the rule states of an SLO
+func (s *Store) SaveRuleStates(sloID string, states []policy.RuleState) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM rule_states WHERE slo_id = ?", sloID); err != nil {
+		return fmt.Errorf("failed to clear rule states: %w", err)
+	}
+
+	for _, state := range states {
+		_, err := tx.Exec(`
+			INSERT INTO rule_states (slo_id, target, rule, pending_since, firing_since, clearing_since)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, sloID, state.Target, state.Rule,
+			nullTime(state.PendingSince), nullTime(state.FiringSince), nullTime(state.ClearingSince))
+		if err != nil {
+			return fmt.Errorf("failed to store rule state %s: %w", state.Rule, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rule states: %w", err)
+	}
+	return nil
+}
+
+// nullTime maps the zero time to NULL
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samijaber1/aegis-slo/internal/policy"
+)
+
+func TestStore_RuleStates(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	states := []policy.RuleState{
+		{SLOID: "checkout", Rule: "fast-burn", FiringSince: now.Add(-time.Hour), ClearingSince: now.Add(-time.Minute)},
+		{SLOID: "checkout", Target: "p99", Rule: "slow-burn", PendingSince: now},
+	}
+	if err := store.SaveRuleStates("checkout", states); err != nil {
+		t.Fatalf("failed to save rule states: %v", err)
+	}
+	if err := store.SaveRuleStates("payment", []policy.RuleState{{SLOID: "payment", Rule: "fast-burn", PendingSince: now}}); err != nil {
+		t.Fatalf("failed to save rule states: %v", err)
+	}
+
+	loaded, err := store.LoadRuleStates()
+	if err != nil {
+		t.Fatalf("failed to load rule states: %v", err)
+	}
+	if len(loaded) != 3 {
+		t.Fatalf("expected 3 rule states, got %+v", loaded)
+	}
+	if first := loaded[0]; first.Rule != "fast-burn" || !first.FiringSince.Equal(states[0].FiringSince) ||
+		!first.ClearingSince.Equal(states[0].ClearingSince) || !first.PendingSince.IsZero() {
+		t.Errorf("expected %+v, got %+v", states[0], first)
+	}
+
+	// Saving replaces the states of the SLO only
+	if err := store.SaveRuleStates("checkout", nil); err != nil {
+		t.Fatalf("failed to save rule states: %v", err)
+	}
+	loaded, err = store.LoadRuleStates()
+	if err != nil {
+		t.Fatalf("failed to load rule states: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].SLOID != "payment" {
+		t.Errorf("expected only the payment state, got %+v", loaded)
+	}
+}
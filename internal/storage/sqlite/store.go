@@ -37,9 +37,42 @@ func NewStore(dbPath string) (*Store, error) {
 		return nil, fmt.Errorf("failed to create schema: %w", err)
 	}
 
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	return &Store{db: db}, nil
 }
 
+// migrate applies the migrations the database has not seen yet, tracked by user_version
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := version; i < len(Migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(Migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
 // StoreSLODefinition persists an SLO definition
 func (s *Store) StoreSLODefinition(sloSpec *slo.SLO) error {
 	specJSON, err := json.Marshal(sloSpec.Spec)
@@ -65,7 +98,7 @@ func (s *Store) StoreSLODefinition(sloSpec *slo.SLO) error {
 		sloSpec.Metadata.Service,
 		sloSpec.Spec.Environment,
 		sloSpec.Spec.Objective,
-		sloSpec.Spec.ComplianceWindow,
+		sloSpec.Spec.ComplianceWindowName(),
 		sloSpec.Spec.EvaluationInterval,
 		string(specJSON),
 	)
@@ -96,14 +129,31 @@ func (s *Store) StoreEvaluation(evalResult *eval.EvaluationResult, gateResult *p
 		return fmt.Errorf("failed to marshal burn rates: %w", err)
 	}
 
+	var forecastJSON, budgetEventsJSON sql.NullString
+	if evalResult.Forecast != nil {
+		data, err := json.Marshal(evalResult.Forecast)
+		if err != nil {
+			return fmt.Errorf("failed to marshal forecast: %w", err)
+		}
+		forecastJSON = sql.NullString{String: string(data), Valid: true}
+	}
+	if evalResult.BudgetEvents != nil {
+		data, err := json.Marshal(evalResult.BudgetEvents)
+		if err != nil {
+			return fmt.Errorf("failed to marshal budget events: %w", err)
+		}
+		budgetEventsJSON = sql.NullString{String: string(data), Valid: true}
+	}
+
 	hasNoTraffic := evalResult.InsufficientData
 
 	query := `
 		INSERT INTO evaluations (
 			slo_id, service, environment, decision, sli, error_rate, budget_remaining,
-			is_stale, has_no_traffic, reasons_json, burn_rates_json, timestamp
+			is_stale, has_no_traffic, reasons_json, burn_rates_json, timestamp, backfilled, query_failed,
+			forecast_json, budget_events_json
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err = s.db.Exec(query,
@@ -119,6 +169,10 @@ func (s *Store) StoreEvaluation(evalResult *eval.EvaluationResult, gateResult *p
 		string(reasonsJSON),
 		string(burnRatesJSON),
 		evalResult.Timestamp,
+		evalResult.Backfilled,
+		evalResult.Failed(),
+		forecastJSON,
+		budgetEventsJSON,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to store evaluation: %w", err)
@@ -152,9 +206,9 @@ func (s *Store) UpdateLatestState(sloID string, evalResult *eval.EvaluationResul
 	query := `
 		INSERT INTO latest_state (
 			slo_id, service, environment, decision, sli, error_rate, budget_remaining,
-			is_stale, has_no_traffic, reasons_json, burn_rates_json, timestamp
+			is_stale, has_no_traffic, reasons_json, burn_rates_json, timestamp, query_failed
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(slo_id) DO UPDATE SET
 			service = excluded.service,
 			environment = excluded.environment,
@@ -167,6 +221,7 @@ func (s *Store) UpdateLatestState(sloID string, evalResult *eval.EvaluationResul
 			reasons_json = excluded.reasons_json,
 			burn_rates_json = excluded.burn_rates_json,
 			timestamp = excluded.timestamp,
+			query_failed = excluded.query_failed,
 			updated_at = CURRENT_TIMESTAMP
 	`
 
@@ -183,6 +238,7 @@ func (s *Store) UpdateLatestState(sloID string, evalResult *eval.EvaluationResul
 		string(reasonsJSON),
 		string(burnRatesJSON),
 		evalResult.Timestamp,
+		evalResult.Failed(),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update latest state: %w", err)
@@ -195,7 +251,8 @@ func (s *Store) UpdateLatestState(sloID string, evalResult *eval.EvaluationResul
 func (s *Store) QueryAudit(filter storage.AuditFilter) ([]storage.AuditRecord, error) {
 	query := `
 		SELECT id, slo_id, service, environment, decision, sli, error_rate, budget_remaining,
-		       is_stale, has_no_traffic, reasons_json, burn_rates_json, timestamp, created_at
+		       is_stale, has_no_traffic, reasons_json, burn_rates_json, timestamp, created_at, backfilled, query_failed,
+		       forecast_json, budget_events_json
 		FROM evaluations
 		WHERE 1=1
 	`
@@ -221,6 +278,11 @@ func (s *Store) QueryAudit(filter storage.AuditFilter) ([]storage.AuditRecord, e
 		args = append(args, filter.Decision)
 	}
 
+	if filter.Backfilled != nil {
+		query += " AND backfilled = ?"
+		args = append(args, *filter.Backfilled)
+	}
+
 	if filter.StartTime != nil {
 		query += " AND timestamp >= ?"
 		args = append(args, filter.StartTime)
@@ -255,6 +317,7 @@ func (s *Store) QueryAudit(filter storage.AuditFilter) ([]storage.AuditRecord, e
 	for rows.Next() {
 		var record storage.AuditRecord
 		var reasonsJSON, burnRatesJSON string
+		var forecastJSON, budgetEventsJSON sql.NullString
 
 		err := rows.Scan(
 			&record.ID,
@@ -271,6 +334,10 @@ func (s *Store) QueryAudit(filter storage.AuditFilter) ([]storage.AuditRecord, e
 			&burnRatesJSON,
 			&record.Timestamp,
 			&record.CreatedAt,
+			&record.Backfilled,
+			&record.QueryFailed,
+			&forecastJSON,
+			&budgetEventsJSON,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
@@ -284,6 +351,18 @@ func (s *Store) QueryAudit(filter storage.AuditFilter) ([]storage.AuditRecord, e
 			return nil, fmt.Errorf("failed to unmarshal burn rates: %w", err)
 		}
 
+		if forecastJSON.Valid {
+			if err := json.Unmarshal([]byte(forecastJSON.String), &record.Forecast); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal forecast: %w", err)
+			}
+		}
+
+		if budgetEventsJSON.Valid {
+			if err := json.Unmarshal([]byte(budgetEventsJSON.String), &record.BudgetEvents); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal budget events: %w", err)
+			}
+		}
+
 		records = append(records, record)
 	}
 
@@ -298,7 +377,7 @@ func (s *Store) QueryAudit(filter storage.AuditFilter) ([]storage.AuditRecord, e
 func (s *Store) GetLatestState(sloID string) (*storage.LatestState, error) {
 	query := `
 		SELECT slo_id, service, environment, decision, sli, error_rate, budget_remaining,
-		       is_stale, has_no_traffic, reasons_json, burn_rates_json, timestamp, updated_at
+		       is_stale, has_no_traffic, reasons_json, burn_rates_json, timestamp, updated_at, query_failed
 		FROM latest_state
 		WHERE slo_id = ?
 	`
@@ -320,6 +399,7 @@ func (s *Store) GetLatestState(sloID string) (*storage.LatestState, error) {
 		&burnRatesJSON,
 		&state.Timestamp,
 		&state.UpdatedAt,
+		&state.QueryFailed,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
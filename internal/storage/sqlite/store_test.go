@@ -1,7 +1,9 @@
 package sqlite
 
 import (
+	"database/sql"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -270,3 +272,121 @@ func TestStore_GetLatestState_NotFound(t *testing.T) {
 		t.Error("expected nil state for nonexistent SLO")
 	}
 }
+
+func TestStore_MigratesExistingDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	// A database created before migrations existed
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if _, err := db.Exec(Schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	db.Close()
+
+	for i := 0; i < 2; i++ {
+		store, err := NewStore(path)
+		if err != nil {
+			t.Fatalf("open %d: failed to migrate: %v", i, err)
+		}
+
+		var version int
+		if err := store.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+			t.Fatalf("failed to read version: %v", err)
+		}
+		if version != len(Migrations) {
+			t.Errorf("open %d: expected user_version %d, got %d", i, len(Migrations), version)
+		}
+		store.Close()
+	}
+}
+
+func TestStore_QueryAuditBackfilled(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	sloSpec := &slo.SLO{
+		Metadata: slo.Metadata{ID: "test-slo", Service: "test-service"},
+		Spec:     slo.Spec{Environment: "production", Objective: 0.995, ComplianceWindow: "30d", EvaluationInterval: "5m"},
+	}
+	if err := store.StoreSLODefinition(sloSpec); err != nil {
+		t.Fatalf("failed to store SLO definition: %v", err)
+	}
+
+	gateResult := &policy.GateResult{Decision: policy.DecisionALLOW, Reasons: []string{}}
+	for _, backfilled := range []bool{false, true, true} {
+		evalResult := &eval.EvaluationResult{SLOID: "test-slo", Timestamp: time.Now(), Backfilled: backfilled}
+		if err := store.StoreEvaluation(evalResult, gateResult); err != nil {
+			t.Fatalf("failed to store evaluation: %v", err)
+		}
+	}
+
+	all, err := store.QueryAudit(storage.AuditFilter{SLOID: "test-slo"})
+	if err != nil {
+		t.Fatalf("failed to query audit: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("expected 3 records, got %d", len(all))
+	}
+
+	live := false
+	records, err := store.QueryAudit(storage.AuditFilter{SLOID: "test-slo", Backfilled: &live})
+	if err != nil {
+		t.Fatalf("failed to query audit: %v", err)
+	}
+	if len(records) != 1 || records[0].Backfilled {
+		t.Errorf("expected 1 live record, got %+v", records)
+	}
+}
+
+func TestStore_QueryAuditBudget(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	sloSpec := &slo.SLO{
+		Metadata: slo.Metadata{ID: "test-slo", Service: "test-service"},
+		Spec:     slo.Spec{Environment: "production", Objective: 0.995, ComplianceWindow: "30d", EvaluationInterval: "5m"},
+	}
+	if err := store.StoreSLODefinition(sloSpec); err != nil {
+		t.Fatalf("failed to store SLO definition: %v", err)
+	}
+
+	tte := 36 * time.Hour
+	gateResult := &policy.GateResult{Decision: policy.DecisionALLOW, Reasons: []string{}}
+	evalResults := []*eval.EvaluationResult{
+		{SLOID: "test-slo", Timestamp: time.Now().Add(-time.Minute)},
+		{SLOID: "test-slo", Timestamp: time.Now(), Forecast: &eval.Forecast{
+			Basis:                    eval.ForecastBasisBurnRate,
+			Window:                   "6h",
+			BurnRate:                 20,
+			TimeToExhaustion:         &tte,
+			ProjectedBudgetRemaining: 0,
+		}, BudgetEvents: &eval.BudgetEvents{Allowed: 5000, Consumed: 1800, Remaining: 3200}},
+	}
+	for _, evalResult := range evalResults {
+		if err := store.StoreEvaluation(evalResult, gateResult); err != nil {
+			t.Fatalf("failed to store evaluation: %v", err)
+		}
+	}
+
+	records, err := store.QueryAudit(storage.AuditFilter{SLOID: "test-slo"})
+	if err != nil {
+		t.Fatalf("failed to query audit: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	forecast := records[0].Forecast
+	if forecast == nil || forecast.BurnRate != 20 || forecast.TimeToExhaustion == nil || *forecast.TimeToExhaustion != tte {
+		t.Errorf("expected the forecast to round-trip, got %+v", forecast)
+	}
+	if budget := records[0].BudgetEvents; budget == nil || *budget != (eval.BudgetEvents{Allowed: 5000, Consumed: 1800, Remaining: 3200}) {
+		t.Errorf("expected the budget events to round-trip, got %+v", budget)
+	}
+	if records[1].Forecast != nil || records[1].BudgetEvents != nil {
+		t.Errorf("expected no forecast or budget events, got %+v", records[1])
+	}
+}
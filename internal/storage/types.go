@@ -29,12 +29,80 @@ type AuditStorage interface {
 	Close() error
 }
 
+// OverrideStorage persists decision overrides and records their creation, use and
+// expiry. Implemented by stores that support overrides.
+type OverrideStorage interface {
+	// CreateOverride persists an override, setting its ID, and records its creation
+	CreateOverride(override *Override) error
+
+	// UseOverride returns the most recently created override of an SLO active at the
+	// given time; nil when there is none. Its use is recorded each time it starts
+	// changing originalDecision, so repeated polls of the same decision record it once.
+	UseOverride(sloID string, originalDecision string, at time.Time) (*Override, error)
+
+	// ListOverrides returns the overrides active at the given time, optionally of one SLO
+	ListOverrides(sloID string, at time.Time) ([]Override, error)
+
+	// ExpireOverrides records the expiry of the overrides that expired by the given time
+	// and returns them; each expiry is recorded once
+	ExpireOverrides(at time.Time) ([]Override, error)
+
+	// QueryOverrideEvents retrieves override events, most recent first
+	QueryOverrideEvents(filter OverrideEventFilter) ([]OverrideEvent, error)
+}
+
+// RuleStateStorage persists the hysteresis state of burn rules so that pending and fired
+// rules survive restarts. Implemented by stores that support it.
+type RuleStateStorage interface {
+	// LoadRuleStates returns the rule states of all SLOs
+	LoadRuleStates() ([]policy.RuleState, error)
+
+	// SaveRuleStates replaces the rule states of an SLO
+	SaveRuleStates(sloID string, states []policy.RuleState) error
+}
+
+// Override forces the gate decision of an SLO until it expires
+type Override struct {
+	ID        int64
+	SLOID     string
+	Decision  string // ALLOW, WARN or BLOCK
+	Approver  string
+	Reason    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Override event types
+const (
+	OverrideCreated = "created"
+	OverrideUsed    = "used"
+	OverrideExpired = "expired"
+)
+
+// OverrideEvent records the creation, use or expiry of an override
+type OverrideEvent struct {
+	ID               int64
+	OverrideID       int64
+	SLOID            string
+	Event            string
+	Decision         string // the override's decision
+	OriginalDecision string // used events only: the decision the override replaced
+	Timestamp        time.Time
+}
+
+// OverrideEventFilter defines filtering options for override event queries
+type OverrideEventFilter struct {
+	SLOID string
+	Limit int
+}
+
 // AuditFilter defines filtering options for audit queries
 type AuditFilter struct {
 	SLOID       string
 	Service     string
 	Environment string
 	Decision    string // ALLOW, BLOCK, WARN
+	Backfilled  *bool  // nil for both live and backfilled records
 	StartTime   *time.Time
 	EndTime     *time.Time
 	Limit       int
@@ -57,6 +125,10 @@ type AuditRecord struct {
 	BurnRates       map[string]eval.BurnRateResult
 	Timestamp       time.Time
 	CreatedAt       time.Time
+	Backfilled      bool               // written by a backfill rather than a live evaluation
+	QueryFailed     bool               // the evaluation or some of its queries failed
+	Forecast        *eval.Forecast     // nil when the budget was unknown
+	BudgetEvents    *eval.BudgetEvents // nil when the budget was unknown
 }
 
 // LatestState represents the most recent evaluation state for an SLO
@@ -74,4 +146,5 @@ type LatestState struct {
 	BurnRates       map[string]eval.BurnRateResult
 	Timestamp       time.Time
 	UpdatedAt       time.Time
+	QueryFailed     bool
 }